@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zvdy/parsero-go/internal/policy"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/export"
+)
+
+func diffCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Compare two JSON exports of the same target",
+		ArgsUsage: "OLD.json NEW.json",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: text, json or markdown",
+				Value: "text",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the diff to a file instead of stdout",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 2 {
+				return cli.Exit("diff needs exactly two files: OLD.json NEW.json", policy.ExitUsage)
+			}
+			oldPath, newPath := c.Args().Get(0), c.Args().Get(1)
+			old, err := export.LoadFile(oldPath)
+			if err != nil {
+				return cli.Exit(err.Error(), policy.ExitUsage)
+			}
+			cur, err := export.LoadFile(newPath)
+			if err != nil {
+				return cli.Exit(err.Error(), policy.ExitUsage)
+			}
+			if old.URL != cur.URL {
+				fmt.Fprintln(c.App.ErrWriter, colors.YELLOW+"[!] Comparing different targets: "+old.URL+" and "+cur.URL+colors.ENDC)
+			}
+
+			w := c.App.Writer
+			if out := c.String("output"); out != "" {
+				f, err := os.Create(out)
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				defer f.Close()
+				w = f
+			}
+			return writeDiff(w, c.String("format"),
+				scanRef{File: oldPath, Target: old.URL, Timestamp: old.Timestamp},
+				scanRef{File: newPath, Target: cur.URL, Timestamp: cur.Timestamp},
+				computeDrift(old, cur))
+		},
+	}
+}
+
+func writeDiff(w io.Writer, format string, old, cur scanRef, d drift) error {
+	switch format {
+	case "text", "":
+		if d.empty() {
+			fmt.Fprintln(w, colors.OKGREEN+"[+] No exposure changes"+colors.ENDC)
+			return nil
+		}
+		printDrift(w, old.File, cur.File, d)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(newDriftReport(old, cur, d))
+	case "markdown", "md":
+		writeDriftMarkdown(w, old, cur, d)
+	default:
+		return cli.Exit("unknown format "+format+" (want text, json or markdown)", policy.ExitUsage)
+	}
+	return nil
+}
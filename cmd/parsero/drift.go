@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/zvdy/parsero-go/internal/diff"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/export"
+)
+
+// drift compares two scans of one target. Removed Disallow entries are
+// reported but don't fail verification: less advertised is never more
+// exposed.
+type drift struct {
+	diff.Result
+	AddedDisallow   []string
+	RemovedDisallow []string
+}
+
+func computeDrift(baseline, cur export.ScanResult) drift {
+	d := drift{Result: diff.Compute(diff.FromResults(baseline.Results), diff.FromResults(cur.Results))}
+	d.AddedDisallow, d.RemovedDisallow = diff.Entries(baseline.Disallow, cur.Disallow)
+	return d
+}
+
+func (d drift) empty() bool {
+	return !d.failing() && len(d.RemovedDisallow) == 0 && len(d.NoLongerReachable) == 0
+}
+
+func (d drift) failing() bool {
+	return len(d.AddedDisallow) > 0 || len(d.NewlyReachable) > 0 || len(d.StatusChanged) > 0
+}
+
+// printDrift writes d as a unified-diff-style listing between the from and to
+// labels.
+func printDrift(w io.Writer, from, to string, d drift) {
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", from, to)
+	for _, e := range d.AddedDisallow {
+		fmt.Fprintln(w, colors.FAIL+"+ Disallow: /"+e+colors.ENDC)
+	}
+	for _, e := range d.RemovedDisallow {
+		fmt.Fprintln(w, colors.YELLOW+"- Disallow: /"+e+colors.ENDC)
+	}
+	for _, u := range d.NewlyReachable {
+		fmt.Fprintln(w, colors.FAIL+"! newly reachable: "+u+colors.ENDC)
+	}
+	for _, u := range d.NoLongerReachable {
+		fmt.Fprintln(w, colors.YELLOW+"  no longer reachable: "+u+colors.ENDC)
+	}
+	moved := make(map[string]bool)
+	for _, u := range append(d.NewlyReachable, d.NoLongerReachable...) {
+		moved[u] = true
+	}
+	for _, ch := range d.StatusChanged {
+		if moved[ch.URL] {
+			continue // already listed above
+		}
+		fmt.Fprintf(w, "~ %s: %d %s -> %d %s\n", ch.URL, ch.From, ch.FromClass, ch.To, ch.ToClass)
+	}
+}
+
+// driftReport is the JSON form of a drift, for `parsero diff --format json`.
+type driftReport struct {
+	Old               scanRef        `json:"old"`
+	New               scanRef        `json:"new"`
+	Changed           bool           `json:"changed"`
+	AddedDisallow     []string       `json:"added_disallow"`
+	RemovedDisallow   []string       `json:"removed_disallow"`
+	NewlyReachable    []string       `json:"newly_reachable"`
+	NoLongerReachable []string       `json:"no_longer_reachable"`
+	StatusChanged     []statusChange `json:"status_changed"`
+}
+
+type scanRef struct {
+	File      string `json:"file"`
+	Target    string `json:"target"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+type statusChange struct {
+	URL       string `json:"url"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	FromClass string `json:"from_class"`
+	ToClass   string `json:"to_class"`
+}
+
+func newDriftReport(old, cur scanRef, d drift) driftReport {
+	r := driftReport{
+		Old: old, New: cur, Changed: !d.empty(),
+		AddedDisallow:     nonNil(d.AddedDisallow),
+		RemovedDisallow:   nonNil(d.RemovedDisallow),
+		NewlyReachable:    nonNil(d.NewlyReachable),
+		NoLongerReachable: nonNil(d.NoLongerReachable),
+		StatusChanged:     []statusChange{},
+	}
+	for _, ch := range d.StatusChanged {
+		r.StatusChanged = append(r.StatusChanged, statusChange(ch))
+	}
+	return r
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// writeDriftMarkdown renders d for pasting into a change ticket.
+func writeDriftMarkdown(w io.Writer, old, cur scanRef, d drift) {
+	fmt.Fprintf(w, "## Exposure changes for %s\n\n", cur.Target)
+	fmt.Fprintf(w, "Comparing `%s` (%s) with `%s` (%s).\n\n", old.File, orUnknown(old.Timestamp), cur.File, orUnknown(cur.Timestamp))
+	if d.empty() {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "### %s\n\n", title)
+		for _, it := range items {
+			fmt.Fprintf(w, "- `%s`\n", it)
+		}
+		fmt.Fprintln(w)
+	}
+	list("Newly reachable", d.NewlyReachable)
+	list("No longer reachable", d.NoLongerReachable)
+	list("Added Disallow entries", prefixAll("/", d.AddedDisallow))
+	list("Removed Disallow entries", prefixAll("/", d.RemovedDisallow))
+	if len(d.StatusChanged) > 0 {
+		fmt.Fprintf(w, "### Status changes\n\n| URL | Before | After |\n|---|---|---|\n")
+		for _, ch := range d.StatusChanged {
+			fmt.Fprintf(w, "| `%s` | %d %s | %d %s |\n", ch.URL, ch.From, ch.FromClass, ch.To, ch.ToClass)
+		}
+	}
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "undated"
+	}
+	return s
+}
+
+func prefixAll(prefix string, items []string) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = prefix + it
+	}
+	return out
+}
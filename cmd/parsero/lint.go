@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zvdy/parsero-go/internal/policy"
+	"github.com/zvdy/parsero-go/internal/sarif"
+	"github.com/zvdy/parsero-go/internal/targets"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/lint"
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+func lintCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "Check a robots.txt for mistakes and information leaks",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "Fetch robots.txt from this host",
+			},
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "Lint a local robots.txt file",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: text, json or sarif",
+				Value: "text",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the report to a file instead of stdout",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			url, file := c.String("url"), c.String("file")
+			if (url == "") == (file == "") {
+				return cli.Exit("lint needs exactly one of --url or --file", policy.ExitUsage)
+			}
+			format := c.String("format")
+			switch format {
+			case "text", "json", "sarif", "":
+			default:
+				return cli.Exit("unknown format "+format+" (want text, json or sarif)", policy.ExitUsage)
+			}
+
+			var (
+				data []byte
+				uri  string
+				err  error
+			)
+			if file != "" {
+				data, err = os.ReadFile(file)
+				uri = file
+			} else {
+				target, perr := targets.Parse(url)
+				if perr != nil {
+					return cli.Exit(perr.Error(), policy.ExitUsage)
+				}
+				data, err = scanner.New().FetchRobots(context.Background(), target)
+				uri = scanner.BaseURL(target) + "/robots.txt"
+			}
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+
+			w := c.App.Writer
+			if out := c.String("output"); out != "" {
+				f, err := os.Create(out)
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				defer f.Close()
+				w = f
+			}
+			return writeLint(w, format, uri, lint.Lint(data))
+		},
+	}
+}
+
+func writeLint(w io.Writer, format, uri string, findings []lint.Finding) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		return enc.Encode(findings)
+	case "sarif":
+		return sarif.Write(w, sarif.BuildLint(uri, findings))
+	case "text", "":
+		if len(findings) == 0 {
+			fmt.Fprintln(w, colors.OKGREEN+uri+": no problems found"+colors.ENDC)
+			return nil
+		}
+		for _, f := range findings {
+			color := colors.YELLOW
+			if f.Severity == lint.SeverityError {
+				color = colors.FAIL
+			}
+			fmt.Fprintf(w, "%s%s:%d: %s: %s [%s]%s\n", color, uri, f.Line, f.Severity, f.Message, f.RuleID, colors.ENDC)
+		}
+		return nil
+	default:
+		return cli.Exit("unknown format "+format+" (want text, json or sarif)", policy.ExitUsage)
+	}
+}
@@ -1,168 +1,93 @@
 package main
 
 import (
-	"bufio"
-	"context"
 	"fmt"
+	"io"
 	"os"
-	"runtime"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
-	"github.com/zvdy/parsero-go/internal/logo"
-	"github.com/zvdy/parsero-go/internal/scanner"
+	"github.com/zvdy/parsero-go/internal/fingerprint"
+	"github.com/zvdy/parsero-go/internal/sarif"
+	"github.com/zvdy/parsero-go/pkg/classify"
 	"github.com/zvdy/parsero-go/pkg/colors"
 	"github.com/zvdy/parsero-go/pkg/export"
+	"github.com/zvdy/parsero-go/pkg/har"
+	"github.com/zvdy/parsero-go/pkg/scanner"
 	"github.com/zvdy/parsero-go/pkg/types"
 )
 
 func main() {
-	app := &cli.App{
+	if err := newApp().Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// newApp builds the CLI. Scanning is the "scan" subcommand; the root still
+// accepts the scan flags (hidden from its help) so "parsero --url x" keeps
+// working.
+func newApp() *cli.App {
+	return &cli.App{
 		Name:  "parsero",
 		Usage: "A Go based Robots.txt audit tool",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:  "url",
-				Usage: "Type the URL which will be analyzed",
-			},
-			&cli.BoolFlag{
-				Name:  "only200",
-				Usage: "Show only the 'HTTP 200' status code",
-			},
-			&cli.StringFlag{
-				Name:  "file",
-				Usage: "Scan a list of domains from a list",
-			},
-			&cli.BoolFlag{
-				Name:    "search-disallow",
-				Aliases: []string{"sb"},
-				Usage:   "Search for disallowed entries using Bing (optional)",
-			},
-			&cli.IntFlag{
-				Name:    "concurrency",
-				Aliases: []string{"c"},
-				Usage:   "Number of concurrent workers (default: number of CPU cores)",
-				Value:   runtime.NumCPU(),
-			},
-			&cli.StringFlag{
-				Name:    "json",
-				Aliases: []string{"j"},
-				Usage:   "Export results to JSON file (specify filename)",
-			},
+		Commands: []*cli.Command{
+			scanCommand(),
+			lintCommand(),
+			verifyCommand(),
+			diffCommand(),
+			reportCommand(),
+			remoteCommand(),
+		},
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
-				Name:  "json-stdout",
-				Usage: "Print JSON results to stdout instead of normal output",
+				Name:  "no-color",
+				Usage: "Disable colored output (also set by a non-empty NO_COLOR)",
 			},
-		},
-		Action: func(c *cli.Context) error {
-			url := c.String("url")
-			only200 := c.Bool("only200")
-			file := c.String("file")
-			searchDisallow := c.Bool("search-disallow")
-			concurrency := c.Int("concurrency")
-			jsonFile := c.String("json")
-			jsonStdout := c.Bool("json-stdout")
-
-			if url == "" && file == "" {
-				logo.PrintLogo()
-				cli.ShowAppHelp(c)
-				return nil
-			}
-
-			var urls []string
-			if file != "" {
-				f, err := os.Open(file)
-				if err != nil {
-					logo.PrintLogo()
-					fmt.Println(colors.FAIL + "[-] The file '" + file + "' doesn't exist." + colors.ENDC)
-					return nil
-				}
-				defer f.Close()
-
-				scn := bufio.NewScanner(f)
-				for scn.Scan() {
-					urls = append(urls, scn.Text())
-				}
-			}
-
-			if url != "" {
-				urls = append(urls, url)
-			}
-
-			if !jsonStdout {
-				logo.PrintLogo()
-			}
-
-			for _, u := range urls {
-				u = strings.TrimPrefix(u, "http://")
-				startTime := time.Now()
-
-				if !jsonStdout {
-					printDate(u)
-				}
-
-				sc := scanner.New(nil, scanner.Options{
-					Only200:     only200,
-					SearchBing:  searchDisallow,
-					Concurrency: concurrency,
-				})
-
-				results, disallow, err := sc.Run(context.Background(), u)
-				if err != nil {
-					if !jsonStdout {
-						fmt.Println(colors.FAIL + err.Error() + colors.ENDC)
-					}
-				} else if len(disallow) == 0 {
-					if !jsonStdout {
-						fmt.Println(colors.YELLOW + "No Disallow entries found in robots.txt." + colors.ENDC)
-					}
-				} else if !jsonStdout {
-					fmt.Printf("Found %d Disallow entries. Processing with %d workers...\n", len(disallow), concurrency)
-					printResults(results, only200)
-				}
-
-				duration := time.Since(startTime)
-
-				if jsonFile != "" || jsonStdout {
-					scanResult := export.CreateScanResult(u, duration, results, only200)
-
-					if jsonStdout {
-						jsonStr, err := export.ToJSON(scanResult)
-						if err != nil {
-							fmt.Println(colors.FAIL + "Error creating JSON output: " + err.Error() + colors.ENDC)
-						} else {
-							fmt.Println(jsonStr)
-						}
-					}
-
-					if jsonFile != "" {
-						err := export.SaveToFile(scanResult, jsonFile)
-						if err != nil {
-							fmt.Println(colors.FAIL + "Error saving JSON to file: " + err.Error() + colors.ENDC)
-						} else if !jsonStdout {
-							fmt.Println(colors.OKGREEN + "Results exported to " + jsonFile + colors.ENDC)
-						}
-					}
-				}
-
-				if !jsonStdout {
-					fmt.Printf("\nFinished in %.2f seconds.\n", duration.Seconds())
-				}
+		}, hidden(scanFlags())...),
+		Before: func(c *cli.Context) error {
+			if c.Bool("no-color") || os.Getenv("NO_COLOR") != "" {
+				colors.Disable()
 			}
 			return nil
 		},
+		Action: runScan,
 	}
+}
 
-	err := app.Run(os.Args)
-	if err != nil {
-		fmt.Println(err)
+// hidden hides flags from help output without changing how they parse.
+func hidden(flags []cli.Flag) []cli.Flag {
+	for _, f := range flags {
+		switch f := f.(type) {
+		case *cli.StringFlag:
+			f.Hidden = true
+		case *cli.BoolFlag:
+			f.Hidden = true
+		case *cli.IntFlag:
+			f.Hidden = true
+		case *cli.Float64Flag:
+			f.Hidden = true
+		case *cli.DurationFlag:
+			f.Hidden = true
+		case *cli.StringSliceFlag:
+			f.Hidden = true
+		}
 	}
+	return flags
+}
+
+// showHelp prints help for whichever command c belongs to.
+func showHelp(c *cli.Context) error {
+	if lineage := c.Lineage(); len(lineage) > 1 && c.Command.Name != "" {
+		return cli.ShowCommandHelp(lineage[1], c.Command.Name)
+	}
+	return cli.ShowAppHelp(c)
 }
 
 // printResults keeps the original CLI output: 200s green, others red unless
 // only200, errors skipped.
-func printResults(results []types.Result, only200 bool) {
+func printResults(w io.Writer, results []types.Result, only200 bool) {
 	for _, r := range results {
 		if r.Error != nil {
 			continue
@@ -171,15 +96,106 @@ func printResults(results []types.Result, only200 bool) {
 		if r.Source == scanner.SourceBing {
 			prefix = " - "
 		}
+		suffix := classLabel(r)
+		if a := r.Archived; a != nil {
+			suffix += " (archived " + a.First.Format("2006-01-02") + " to " + a.Last.Format("2006-01-02") + ")"
+		}
+		if len(r.Fingerprints) > 0 {
+			suffix += " [" + strings.Join(r.Fingerprints, ", ") + "]"
+		}
 		if r.StatusCode == 200 {
-			fmt.Println(colors.OKGREEN + prefix + r.URL + " " + r.Status + colors.ENDC)
+			fmt.Fprintln(w, colors.OKGREEN+prefix+r.URL+" "+r.Status+suffix+colors.ENDC)
 		} else if !only200 {
-			fmt.Println(colors.FAIL + prefix + r.URL + " " + r.Status + colors.ENDC)
+			fmt.Fprintln(w, colors.FAIL+prefix+r.URL+" "+r.Status+suffix+colors.ENDC)
+		} else {
+			continue
+		}
+		for _, f := range r.Findings {
+			line := "    ! " + f.Analyzer + ": " + f.Message
+			if f.Evidence != "" {
+				line += " (" + f.Evidence + ")"
+			}
+			fmt.Fprintln(w, colors.YELLOW+line+colors.ENDC)
+		}
+	}
+}
+
+// classLabel annotates the classifications a status code alone hides.
+func classLabel(r types.Result) string {
+	switch r.Class {
+	case classify.Auth:
+		if r.AuthScheme != "" {
+			return " (auth: " + r.AuthScheme + ")"
+		}
+		return " (auth)"
+	case classify.Login:
+		return " (login page)"
+	case classify.Blocked:
+		return " (waf)"
+	}
+	return ""
+}
+
+func writeHAR(path string, rec *har.Recorder) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := rec.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeSARIF(path string, targets []sarif.Target) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := sarif.Write(f, sarif.Build(targets...)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// streamKeeps applies the --only200 and --tech filters to a streamed result,
+// matching what the end-of-scan reports include.
+func streamKeeps(r types.Result, only200 bool, tech []string) bool {
+	if only200 && r.StatusCode != 200 {
+		return false
+	}
+	return len(tech) == 0 || fingerprint.Matches(r.Fingerprints, tech)
+}
+
+// reportFormat resolves --format/--output: an --output alone picks the format
+// from its extension, falling back to JSON.
+func reportFormat(format, output string) (string, string) {
+	if format != "" || output == "" {
+		return format, output
+	}
+	ext := strings.ToLower(filepath.Ext(output))
+	for _, name := range export.Formats() {
+		if f, _ := export.Lookup(name); f.Extension == ext {
+			return name, output
+		}
+	}
+	return "json", output
+}
+
+// filterByTech keeps results fingerprinted as any of tech.
+func filterByTech(results []types.Result, tech []string) []types.Result {
+	var out []types.Result
+	for _, r := range results {
+		if fingerprint.Matches(r.Fingerprints, tech) {
+			out = append(out, r)
 		}
 	}
+	return out
 }
 
-func printDate(url string) {
-	fmt.Println("Starting Parsero v2.0.0 (https://github.com/zvdy/parsero-go) at " + time.Now().Format("01/02/2006 15:04:05"))
-	fmt.Println("Parsero scan report for " + url)
+func printDate(w io.Writer, url string, started time.Time) {
+	fmt.Fprintln(w, "Starting Parsero v2.0.0 (https://github.com/zvdy/parsero-go) at "+started.Format("01/02/2006 15:04:05"))
+	fmt.Fprintln(w, "Parsero scan report for "+url)
 }
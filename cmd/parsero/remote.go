@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zvdy/parsero-go/internal/policy"
+	"github.com/zvdy/parsero-go/pkg/client"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/export"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// remoteCommand drives a parserod instance over its REST API, so CI jobs can
+// use the central service instead of scanning from their own runners.
+func remoteCommand() *cli.Command {
+	return &cli.Command{
+		Name:            "remote",
+		Usage:           "Run scans and manage monitors on a parserod server",
+		HideHelpCommand: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "server",
+				Aliases: []string{"s"},
+				Usage:   "parserod base URL, e.g. https://parsero.example.com",
+				EnvVars: []string{"PARSERO_SERVER"},
+			},
+			&cli.StringFlag{
+				Name:    "api-key",
+				Usage:   "Send 'Authorization: Bearer KEY' (for an auth proxy in front of parserod)",
+				EnvVars: []string{"PARSERO_API_KEY"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "header",
+				Aliases: []string{"H"},
+				Usage:   "Extra request header 'Name: value', e.g. the identity header 'X-Auth-Request-Email: ci@example.com' (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "proxy",
+				Usage: "HTTP(S) proxy URL for requests to the server",
+			},
+		},
+		Subcommands: []*cli.Command{
+			remoteScanCommand(),
+			remoteResultsCommand(),
+			remoteSchedulesCommand(),
+		},
+	}
+}
+
+func remoteScanCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "scan",
+		Usage:     "Submit a scan, wait for it to finish and gate on the results",
+		ArgsUsage: "TARGET",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "only200",
+				Usage: "Only show 'HTTP 200' status code",
+			},
+			&cli.BoolFlag{
+				Name:    "search-disallow",
+				Aliases: []string{"sb"},
+				Usage:   "Search for disallowed entries using Bing",
+			},
+			&cli.StringSliceFlag{
+				Name:  "fail-on",
+				Usage: "Exit non-zero when a rule matches, as for a local scan (repeatable)",
+			},
+			&cli.BoolFlag{
+				Name:  "har",
+				Usage: "Record the scan's HTTP traffic on the server; download it with 'remote results --format har'",
+			},
+			&cli.BoolFlag{
+				Name:  "no-wait",
+				Usage: "Print the scan ID and exit without waiting for the results",
+			},
+			&cli.DurationFlag{
+				Name:  "wait-timeout",
+				Usage: "Give up waiting after this long",
+				Value: 10 * time.Minute,
+			},
+		},
+		Action: runRemoteScan,
+	}
+}
+
+func remoteResultsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "results",
+		Usage:     "Download a finished scan's results",
+		ArgsUsage: "SCAN-ID",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Report format: sarif, har, " + strings.Join(export.Formats(), ", ") + " (default: from --output, else json)",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the report to this file instead of stdout",
+			},
+		},
+		Action: runRemoteResults,
+	}
+}
+
+func remoteSchedulesCommand() *cli.Command {
+	return &cli.Command{
+		Name:            "schedules",
+		Usage:           "List and create recurring monitors",
+		HideHelpCommand: true,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List your monitors",
+				Action: runRemoteSchedulesList,
+			},
+			{
+				Name:      "create",
+				Usage:     "Create a monitor that rescans TARGET on a cron schedule",
+				ArgsUsage: "TARGET",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "cron",
+						Usage:    "Cron spec or descriptor, e.g. '0 6 * * *' or '@daily'",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "only200",
+						Usage: "Only record 'HTTP 200' paths",
+					},
+					&cli.BoolFlag{
+						Name:  "search-disallow",
+						Usage: "Search for disallowed entries using Bing",
+					},
+					&cli.StringFlag{
+						Name:  "webhook",
+						Usage: "Webhook URL notified after each run",
+					},
+					&cli.BoolFlag{
+						Name:  "notify-on-change",
+						Usage: "Only notify when the exposure changed since the previous run",
+					},
+					&cli.StringSliceFlag{
+						Name:  "alert-tech",
+						Usage: "Only alert on paths fingerprinted as these technologies (repeatable)",
+					},
+				},
+				Action: runRemoteSchedulesCreate,
+			},
+		},
+	}
+}
+
+// newRemoteClient builds the SDK client from the remote command's flags; the
+// headers and proxy go through the same transport local scans use.
+func newRemoteClient(c *cli.Context) (*client.Client, error) {
+	server := c.String("server")
+	if server == "" {
+		return nil, errors.New("no server: pass --server or set PARSERO_SERVER")
+	}
+	hc, err := newClient(clientOptions{proxy: c.String("proxy"), headers: c.StringSlice("header"), hosts: []string{server}})
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.Option{client.WithHTTPClient(hc)}
+	if key := c.String("api-key"); key != "" {
+		opts = append(opts, client.WithAPIKey(key))
+	}
+	return client.New(server, opts...)
+}
+
+func runRemoteScan(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("remote scan needs exactly one target", policy.ExitUsage)
+	}
+	pol, err := policy.Parse(c.StringSlice("fail-on"))
+	if err != nil {
+		return cli.Exit("invalid --fail-on: "+err.Error(), policy.ExitUsage)
+	}
+	rc, err := newRemoteClient(c)
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	only200 := c.Bool("only200")
+	errOut := c.App.ErrWriter
+
+	ctx := context.Background()
+	sc, err := rc.CreateScan(ctx, client.ScanRequest{
+		Target:     c.Args().First(),
+		Only200:    only200,
+		SearchBing: c.Bool("search-disallow"),
+		HAR:        c.Bool("har"),
+	})
+	if err != nil {
+		return cli.Exit(colors.FAIL+err.Error()+colors.ENDC, policy.ExitScanError)
+	}
+	if c.Bool("no-wait") {
+		fmt.Fprintln(c.App.Writer, sc.ID)
+		return nil
+	}
+	if sc.Cached {
+		fmt.Fprintf(errOut, "[*] Scan %s for %s (cached)\n", sc.ID, sc.Target)
+	} else {
+		fmt.Fprintf(errOut, "[*] Scan %s for %s %s\n", sc.ID, sc.Target, sc.Status)
+	}
+
+	if !sc.Finished() {
+		wctx, cancel := context.WithTimeout(ctx, c.Duration("wait-timeout"))
+		defer cancel()
+		last, target := -1, sc.Target
+		sc, err = rc.Wait(wctx, sc.ID, func(p client.Progress) {
+			if p.Done != last && p.Total > 0 {
+				fmt.Fprintf(errOut, "[*] %d/%d paths probed\n", p.Done, p.Total)
+				last = p.Done
+			}
+		})
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("gave up waiting after %s; the scan keeps running on the server", c.Duration("wait-timeout"))
+			}
+			return cli.Exit(colors.FAIL+err.Error()+colors.ENDC, policy.ExitScanError)
+		}
+		if sc.Target == "" {
+			sc.Target = target
+		}
+	}
+
+	var scanErr error
+	var results []types.Result
+	if sc.Status == client.StatusFailed {
+		scanErr = errors.New(sc.ErrorMessage)
+	} else {
+		rows, err := rc.Results(ctx, sc.ID)
+		if err != nil {
+			return cli.Exit(colors.FAIL+err.Error()+colors.ENDC, policy.ExitScanError)
+		}
+		results = client.ToTypes(rows)
+	}
+
+	w := c.App.Writer
+	printDate(w, sc.Target, sc.CreatedAt.Local())
+	switch {
+	case scanErr != nil:
+		fmt.Fprintln(w, colors.FAIL+scanErr.Error()+colors.ENDC)
+	case len(results) == 0:
+		fmt.Fprintln(w, colors.YELLOW+"No Disallow entries found in robots.txt."+colors.ENDC)
+	default:
+		printResults(w, results, only200)
+	}
+	if sc.Degraded {
+		fmt.Fprintln(w, colors.YELLOW+"[!] Scan degraded: "+sc.DegradedReason+colors.ENDC)
+	}
+	if sc.HAR {
+		fmt.Fprintf(errOut, "[*] HAR recorded; download it with: parsero remote results --format har %s\n", sc.ID)
+	}
+	in := policy.Input{
+		Results:  results,
+		Err:      scanErr,
+		NoRobots: sc.RobotsStatus == http.StatusNotFound,
+		Degraded: sc.Degraded,
+		Reason:   sc.DegradedReason,
+	}
+	code := policy.ScanExit(in)
+	if pol != nil {
+		d := pol.Evaluate(in)
+		printDecision(w, d)
+		code = d.ExitCode
+	}
+	fmt.Fprintf(w, "\nFinished in %.2f seconds.\n", sc.DurationSeconds)
+	if code != policy.ExitOK {
+		return cli.Exit("", code)
+	}
+	return nil
+}
+
+func runRemoteResults(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("remote results needs exactly one scan ID", policy.ExitUsage)
+	}
+	rc, err := newRemoteClient(c)
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+
+	format, output := c.String("format"), c.String("output")
+	if format == "" {
+		switch ext := strings.ToLower(filepath.Ext(output)); ext {
+		case ".sarif", ".har":
+			format = ext[1:]
+		}
+	}
+	format, output = reportFormat(format, output)
+	if format == "" {
+		format = "json"
+	}
+	if _, ok := export.Lookup(format); !ok && format != "sarif" && format != "har" {
+		return cli.Exit("unknown --format "+format+" (want sarif, har or one of "+strings.Join(export.Formats(), ", ")+")", policy.ExitUsage)
+	}
+
+	ctx, id := context.Background(), c.Args().First()
+	var body io.ReadCloser
+	switch format {
+	case "sarif":
+		body, err = rc.SARIF(ctx, id)
+	case "har":
+		body, err = rc.HAR(ctx, id)
+	default:
+		body, err = rc.Report(ctx, id, format)
+	}
+	if err != nil {
+		return cli.Exit(colors.FAIL+err.Error()+colors.ENDC, policy.ExitScanError)
+	}
+	defer body.Close()
+	if output == "" {
+		_, err = io.Copy(c.App.Writer, body)
+		return err
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return cli.Exit(err.Error(), 1)
+	}
+	if err := f.Close(); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	fmt.Fprintln(c.App.ErrWriter, colors.OKGREEN+"Report written to "+output+colors.ENDC)
+	return nil
+}
+
+func runRemoteSchedulesList(c *cli.Context) error {
+	rc, err := newRemoteClient(c)
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	schedules, err := rc.ListSchedules(context.Background())
+	if err != nil {
+		return cli.Exit(colors.FAIL+err.Error()+colors.ENDC, policy.ExitScanError)
+	}
+	if len(schedules) == 0 {
+		fmt.Fprintln(c.App.Writer, "No monitors.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTARGET\tCRON\tENABLED\tLAST RUN")
+	for _, s := range schedules {
+		last := "-"
+		if s.LastRunAt != nil {
+			last = s.LastRunAt.Local().Format("01/02/2006 15:04:05")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\n", s.ID, s.Target, s.Cron, s.Enabled, last)
+	}
+	return tw.Flush()
+}
+
+func runRemoteSchedulesCreate(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("schedules create needs exactly one target", policy.ExitUsage)
+	}
+	rc, err := newRemoteClient(c)
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	created, err := rc.CreateSchedule(context.Background(), client.ScheduleRequest{
+		Target:            c.Args().First(),
+		Cron:              c.String("cron"),
+		Only200:           c.Bool("only200"),
+		SearchBing:        c.Bool("search-disallow"),
+		NotifyWebhook:     c.String("webhook"),
+		NotifyOnChange:    c.Bool("notify-on-change"),
+		AlertFingerprints: c.StringSlice("alert-tech"),
+	})
+	if err != nil {
+		return cli.Exit(colors.FAIL+err.Error()+colors.ENDC, policy.ExitScanError)
+	}
+	fmt.Fprintf(c.App.Writer, "%sMonitor %s created: %s on %q%s\n", colors.OKGREEN, created.ID, created.Target, created.Cron, colors.ENDC)
+	return nil
+}
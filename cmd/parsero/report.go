@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zvdy/parsero-go/internal/policy"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/export"
+)
+
+func reportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "report",
+		Usage:     "Render a JSON export in another report format",
+		ArgsUsage: "SCAN.json",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Report format: " + strings.Join(export.Formats(), ", ") + " (default: from --output, else markdown)",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the report to this file instead of stdout",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("report needs exactly one JSON export", policy.ExitUsage)
+			}
+			res, err := export.LoadFile(c.Args().First())
+			if err != nil {
+				return cli.Exit(err.Error(), policy.ExitUsage)
+			}
+
+			format, output := reportFormat(c.String("format"), c.String("output"))
+			if format == "" {
+				format = "markdown"
+			}
+			if _, ok := export.Lookup(format); !ok {
+				return cli.Exit("unknown --format "+format+" (want one of "+strings.Join(export.Formats(), ", ")+")", policy.ExitUsage)
+			}
+			if output == "" {
+				return export.Write(c.App.Writer, format, res)
+			}
+			if err := export.WriteFile(output, format, res); err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			fmt.Fprintln(c.App.ErrWriter, colors.OKGREEN+"Report written to "+output+colors.ENDC)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,1118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zvdy/parsero-go/internal/logo"
+	"github.com/zvdy/parsero-go/internal/policy"
+	"github.com/zvdy/parsero-go/internal/profile"
+	"github.com/zvdy/parsero-go/internal/quality"
+	"github.com/zvdy/parsero-go/internal/sarif"
+	"github.com/zvdy/parsero-go/internal/state"
+	"github.com/zvdy/parsero-go/internal/targets"
+	"github.com/zvdy/parsero-go/internal/tui"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/export"
+	"github.com/zvdy/parsero-go/pkg/har"
+	"github.com/zvdy/parsero-go/pkg/lint"
+	"github.com/zvdy/parsero-go/pkg/scanner"
+	"github.com/zvdy/parsero-go/pkg/scanner/analyzers"
+	"github.com/zvdy/parsero-go/pkg/types"
+	"golang.org/x/time/rate"
+)
+
+func scanCommand() *cli.Command {
+	return &cli.Command{
+		Name:            "scan",
+		HideHelpCommand: true,
+		Usage:           "Probe a target's Disallow entries (the default when no command is given)",
+		Flags:           scanFlags(),
+		Action:          runScan,
+	}
+}
+
+// scanFlags returns fresh flag values on each call: the root command and
+// "scan" both register them.
+func scanFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "profile",
+			Usage: "Settings profile from the config file (default: the file's 'default')",
+		},
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "Config file (default: ./.parsero.yaml, ./parsero.yaml or $XDG_CONFIG_HOME/parsero/config.yaml)",
+		},
+		&cli.StringFlag{
+			Name:  "url",
+			Usage: "Type the URL which will be analyzed",
+		},
+		&cli.BoolFlag{
+			Name:  "only200",
+			Usage: "Show only the 'HTTP 200' status code",
+		},
+		&cli.StringFlag{
+			Name:  "file",
+			Usage: "Scan a list of domains from a list ('-' for stdin; nmap/masscan output is recognised)",
+		},
+		&cli.BoolFlag{
+			Name:  "expand-cidr",
+			Usage: "Expand CIDR ranges in --file into one target per address",
+		},
+		&cli.StringFlag{
+			Name:  "robots-file",
+			Usage: "Read robots.txt from this file ('-' for stdin) instead of fetching it; probe it against --base",
+		},
+		&cli.StringFlag{
+			Name:  "base",
+			Usage: "Base URL to probe the --robots-file entries against",
+		},
+		&cli.BoolFlag{
+			Name:  "no-probe",
+			Usage: "Only list and lint the Disallow entries; send no probes",
+		},
+		&cli.BoolFlag{
+			Name:    "search-disallow",
+			Aliases: []string{"sb"},
+			Usage:   "Search for disallowed entries using Bing (optional)",
+		},
+		&cli.BoolFlag{
+			Name:  "archive",
+			Usage: "Also probe Disallow entries from archived robots.txt versions (Wayback Machine)",
+		},
+		&cli.StringFlag{
+			Name:  "archive-url",
+			Usage: "Wayback/CDX-compatible archive for --archive (default: " + scanner.DefaultArchiveURL + ")",
+		},
+		&cli.IntFlag{
+			Name:  "archive-versions",
+			Usage: "Read at most this many archived robots.txt versions, spread over the history (default: all)",
+		},
+		&cli.IntFlag{
+			Name:    "concurrency",
+			Aliases: []string{"c"},
+			Usage:   "Number of concurrent workers (default: number of CPU cores)",
+			Value:   runtime.NumCPU(),
+		},
+		&cli.BoolFlag{
+			Name:  "adaptive",
+			Usage: "Adapt the worker count to the target's latency, errors and 429s",
+		},
+		&cli.IntFlag{
+			Name:    "target-concurrency",
+			Aliases: []string{"tc"},
+			Usage:   "Number of targets scanned in parallel with --file",
+			Value:   1,
+		},
+		&cli.Float64Flag{
+			Name:  "max-rps",
+			Usage: "Global request budget per second, shared across all targets (0 = unlimited)",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Timeout for each path probe, e.g. 10s",
+			Value: 3 * time.Second,
+		},
+		&cli.StringSliceFlag{
+			Name:    "header",
+			Aliases: []string{"H"},
+			Usage:   "Extra request header 'Name: value' (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "host-header",
+			Usage: "Request header for one host only, 'host=Name: value', e.g. a per-site token (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "har",
+			Usage: "Record every HTTP exchange, with headers, timings and capped bodies, to this HAR file",
+		},
+		&cli.StringFlag{
+			Name:  "trace",
+			Usage: "Log every HTTP request and response to this file as NDJSON ('-' for stderr)",
+		},
+		&cli.StringFlag{
+			Name:  "user-agent",
+			Usage: "User-Agent for robots.txt fetches and probes (default: " + scanner.DefaultUserAgent + ")",
+		},
+		&cli.StringFlag{
+			Name:  "proxy",
+			Usage: "Send requests through this HTTP or SOCKS5 proxy URL",
+		},
+		&cli.StringSliceFlag{
+			Name:  "scope",
+			Usage: "Only scan targets matching these host globs, IPs or CIDRs (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "out-of-scope",
+			Usage: "Never scan targets matching these host globs, IPs or CIDRs (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "Checkpoint finished targets and paths to this file so an interrupted scan can be resumed",
+		},
+		&cli.BoolFlag{
+			Name:  "resume",
+			Usage: "Continue the scan recorded in --state instead of starting over",
+		},
+		&cli.BoolFlag{
+			Name:  "tui",
+			Usage: "Show an interactive view with live progress per target; reports print when it closes",
+		},
+		&cli.BoolFlag{
+			Name:  "unordered",
+			Usage: "Print each target as soon as it finishes instead of in input order",
+		},
+		&cli.IntFlag{
+			Name:  "min-concurrency",
+			Usage: "Lower bound for --adaptive",
+			Value: 1,
+		},
+		&cli.IntFlag{
+			Name:  "max-concurrency",
+			Usage: "Upper bound for --adaptive (default: 4 x --concurrency)",
+		},
+		&cli.StringFlag{
+			Name:    "json",
+			Aliases: []string{"j"},
+			Usage:   "Export results to JSON file (specify filename)",
+		},
+		&cli.BoolFlag{
+			Name:  "json-stdout",
+			Usage: "Print JSON results to stdout instead of normal output",
+		},
+		&cli.StringSliceFlag{
+			Name:  "fail-on",
+			Usage: "Exit non-zero when a rule matches, e.g. 'sensitive' or 'class=open,login severity=warning count=3' (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "sarif",
+			Usage: "Write reachable Disallow paths as SARIF 2.1.0 to this file (one run per target)",
+		},
+		&cli.BoolFlag{
+			Name:  "ndjson",
+			Usage: "Stream one JSON line per result as it arrives, plus a summary line per target",
+		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Report format: " + strings.Join(export.Formats(), ", ") + " (stdout unless --output is set)",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Write the report to this file (format inferred from the extension if --format is unset)",
+		},
+		&cli.BoolFlag{
+			Name:    "fingerprint",
+			Aliases: []string{"fp"},
+			Usage:   "Fingerprint the technology behind each reachable path",
+		},
+		&cli.BoolFlag{
+			Name:  "inspect",
+			Usage: "Fetch response bodies to recognise login forms and WAF block pages",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tech",
+			Usage: "Only report paths fingerprinted as one of these technologies (implies --fingerprint)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "analyze",
+			Usage: "Run these response analyzers on each reachable path: " + strings.Join(analyzers.Names(), ", "),
+		},
+	}
+}
+
+// exitInterrupted is the shell convention for a process stopped by SIGINT.
+const exitInterrupted = 130
+
+// scanConfig is the per-invocation scan setup shared by every target.
+type scanConfig struct {
+	client *http.Client
+	opts   scanner.Options
+	tech   []string
+	stream *export.NDJSON
+	policy policy.Policy // nil without --fail-on
+	state  *state.Writer // nil without --state
+	resume map[string]*state.Target
+	ui     *tui.Feed // nil without --tui
+	warn   io.Writer // analyzer failures; nil to drop them
+	hooks  []scanner.Option
+
+	// robotsFile is the --robots-file every target's Disallow list is read
+	// from instead of fetching; robots holds its contents.
+	robotsFile string
+	robots     []byte
+	noProbe    bool
+}
+
+// targetRun is one target's finished scan.
+type targetRun struct {
+	Target   string
+	Started  time.Time
+	Duration time.Duration
+	Results  []types.Result
+	Disallow []string
+	Err      error
+	Quality  quality.Assessment
+	Profile  *types.ConcurrencyProfile
+	// RobotsStatus is the robots.txt HTTP status; 0 if it was never fetched.
+	RobotsStatus int
+	Decision     *types.PolicyDecision
+	// Interrupted runs were cut short by a signal; their partial results are
+	// in the state file, not the report.
+	Interrupted bool
+	// NoProbe runs only list and lint the Disallow entries of RobotsSource,
+	// a file or robots.txt URL.
+	NoProbe      bool
+	RobotsSource string
+	Lint         []lint.Finding
+}
+
+func (r targetRun) export(only200 bool) export.ScanResult {
+	sr := export.CreateScanResult(r.Target, r.Duration, r.Results, only200)
+	sr.Timestamp = r.Started.Format(time.RFC3339)
+	sr.Degraded, sr.DegradedReason = r.Quality.Degraded, r.Quality.Reason
+	sr.Concurrency = r.Profile
+	sr.Policy = r.Decision
+	sr.Disallow = r.Disallow
+	sr.Lint = r.Lint
+	return sr
+}
+
+func scanTarget(ctx context.Context, cfg scanConfig, target string) targetRun {
+	prior := cfg.resume[target]
+	if prior != nil && prior.Done != nil {
+		return cfg.finish(restoreRun(target, prior))
+	}
+	run := targetRun{Target: target, Started: time.Now()}
+
+	opts := []scanner.Option{
+		scanner.WithOptions(cfg.opts),
+		scanner.WithHTTPClient(cfg.client),
+		scanner.OnConcurrency(func(p types.ConcurrencyProfile) { run.Profile = &p }),
+		scanner.OnRobots(func(status int) { run.RobotsStatus = status }),
+	}
+	opts = append(opts, cfg.hooks...)
+	if cfg.warn != nil {
+		opts = append(opts, scanner.OnAnalyzerError(func(name, url string, err error) {
+			fmt.Fprintf(cfg.warn, "%s[!] Analyzer %s failed on %s: %v%s\n", colors.YELLOW, name, url, err, colors.ENDC)
+		}), scanner.OnArchive(func(read, total int) {
+			if read < total {
+				fmt.Fprintf(cfg.warn, "%s[!] Archive: read %d of %d robots.txt versions of %s; entries only the rest listed are missing%s\n",
+					colors.YELLOW, read, total, target, colors.ENDC)
+			}
+		}))
+	}
+	if cfg.ui != nil {
+		opts = append(opts, scanner.OnProgress(func(done, total int) { cfg.ui.Progress(target, done, total) }))
+	}
+	if cfg.stream != nil || cfg.state != nil || cfg.ui != nil {
+		opts = append(opts, scanner.OnResult(func(r types.Result, elapsed time.Duration) {
+			if streamKeeps(r, cfg.opts.Only200, cfg.tech) {
+				if cfg.stream != nil {
+					cfg.stream.Result(target, r, elapsed)
+				}
+				cfg.ui.Result(target, r)
+			}
+			// Probes cut short by the interrupt, or that failed in
+			// transport, are redone on resume.
+			if cfg.state != nil && ctx.Err() == nil && r.Error == nil {
+				cfg.state.Result(target, r)
+			}
+		}))
+	}
+	sc := scanner.New(opts...)
+
+	switch {
+	case cfg.noProbe:
+		run.NoProbe = true
+		data := cfg.robots
+		run.RobotsSource = cfg.robotsFile
+		if cfg.robotsFile == "" {
+			data, run.Err = sc.FetchRobots(ctx, target)
+			run.RobotsSource = scanner.BaseURL(target) + "/robots.txt"
+		}
+		if run.Err == nil {
+			run.Disallow, run.Err = sc.ParseDisallowPaths(data)
+			run.Lint = lint.Lint(data)
+		}
+	case prior != nil && prior.Fetched:
+		run.RobotsStatus, run.Disallow = prior.RobotsStatus, prior.Disallow
+		run.Results = append(priorProbes(prior, cfg.opts),
+			sc.Resume(ctx, target, prior.Disallow, prior.Probed())...)
+	case cfg.state != nil || cfg.robotsFile != "":
+		// Run, split so the Disallow list is checkpointed before probing, or
+		// read from --robots-file instead of fetched.
+		if cfg.robotsFile != "" {
+			run.Disallow, run.Err = sc.ParseDisallowPaths(cfg.robots)
+		} else {
+			run.Disallow, run.Err = sc.FetchDisallowPaths(ctx, target)
+		}
+		if run.Err == nil && ctx.Err() == nil {
+			if len(run.Disallow) == 0 {
+				run.Disallow = nil
+			}
+			if cfg.state != nil {
+				cfg.state.Robots(target, run.RobotsStatus, run.Disallow)
+			}
+			if len(run.Disallow) > 0 || cfg.opts.Archive {
+				run.Results = sc.Resume(ctx, target, run.Disallow, nil)
+			}
+		} else if errors.Is(run.Err, scanner.ErrRobotsUnreachable) && cfg.opts.Archive && ctx.Err() == nil {
+			// As in Run: archived entries stand in for an unreachable
+			// robots.txt. Its fetch isn't checkpointed, so --resume retries it.
+			if run.Results = sc.Resume(ctx, target, nil, nil); len(run.Results) > 0 {
+				run.Err = nil
+			}
+		}
+	default:
+		run.Results, run.Disallow, run.Err = sc.Run(ctx, target)
+	}
+	run.Duration = time.Since(run.Started)
+
+	if ctx.Err() != nil {
+		run.Interrupted = true
+		return run
+	}
+	if cfg.state != nil && !retryable(run) {
+		d := state.Done{Started: run.Started, Duration: run.Duration, RobotsStatus: run.RobotsStatus, Concurrency: run.Profile}
+		if run.Err != nil {
+			d.Error = run.Err.Error()
+		}
+		cfg.state.Done(target, d)
+	}
+	return cfg.finish(run)
+}
+
+// retryable reports whether a finished run hit transport failures, so the
+// state file leaves it unfinished for --resume to retry.
+func retryable(run targetRun) bool {
+	if errors.Is(run.Err, scanner.ErrRobotsUnreachable) {
+		return true
+	}
+	return slices.ContainsFunc(run.Results, func(r types.Result) bool { return r.Error != nil })
+}
+
+// finish applies the --tech filter, the quality check and the policy to a
+// completed run.
+func (cfg scanConfig) finish(run targetRun) targetRun {
+	if len(cfg.tech) > 0 {
+		run.Results = filterByTech(run.Results, cfg.tech)
+	}
+	run.Quality = quality.Assess(nil, quality.FromResults(run.Results))
+	if cfg.policy != nil {
+		d := cfg.policy.Evaluate(run.policyInput())
+		run.Decision = &d
+	}
+	return run
+}
+
+func (r targetRun) policyInput() policy.Input {
+	return policy.Input{
+		Results:  r.Results,
+		Err:      r.Err,
+		NoRobots: errors.Is(r.Err, scanner.ErrNoRobots) || r.RobotsStatus == http.StatusNotFound,
+		Degraded: r.Quality.Degraded,
+		Reason:   r.Quality.Reason,
+	}
+}
+
+// restoreRun rebuilds a target the state file records as finished. A missing
+// robots.txt comes back through RobotsStatus, as it does for a live run.
+func restoreRun(target string, t *state.Target) targetRun {
+	run := targetRun{
+		Target:       target,
+		Started:      t.Done.Started,
+		Duration:     t.Done.Duration,
+		Results:      t.Results,
+		Disallow:     t.Disallow,
+		RobotsStatus: t.Done.RobotsStatus,
+		Profile:      t.Done.Concurrency,
+	}
+	if t.Done.Error != "" {
+		run.Err = errors.New(t.Done.Error)
+	}
+	return run
+}
+
+// priorProbes is the checkpointed results a resumed target keeps. Bing and
+// archive results are dropped when those are searched again.
+func priorProbes(t *state.Target, opts scanner.Options) []types.Result {
+	if !opts.SearchBing && !opts.Archive {
+		return slices.Clone(t.Results)
+	}
+	var out []types.Result
+	for _, r := range t.Results {
+		if (opts.SearchBing && r.Source == scanner.SourceBing) || (opts.Archive && r.Source == scanner.SourceArchive) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// scanTargets runs scan over targets with at most workers in flight and hands
+// each finished run to emit — in input order when ordered, else as runs
+// complete. emit is never called concurrently.
+func scanTargets(targets []string, workers int, ordered bool, scan func(string) targetRun, emit func(int, targetRun)) {
+	if workers < 1 {
+		workers = 1
+	}
+	type done struct {
+		i   int
+		run targetRun
+	}
+	work := make(chan int)
+	out := make(chan done)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(targets); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				out <- done{i, scan(targets[i])}
+			}
+		}()
+	}
+	go func() {
+		for i := range targets {
+			work <- i
+		}
+		close(work)
+	}()
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	pending := map[int]targetRun{}
+	next := 0
+	for d := range out {
+		if !ordered {
+			emit(d.i, d.run)
+			continue
+		}
+		pending[d.i] = d.run
+		for {
+			run, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			emit(next, run)
+			next++
+		}
+	}
+}
+
+// budgetTransport makes every request, across all targets, wait on one
+// shared token bucket.
+type budgetTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// headerTransport adds the --header values to requests for hosts that
+// don't already carry them. Other hosts (Bing, the archive, redirects off
+// the targets) never see them: the values are often engagement credentials.
+// It works per hop, so a redirect can't carry them along either.
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+	hosts  map[string]bool // lower-case host names, without port
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.hosts[strings.ToLower(req.URL.Hostname())] {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// clientOptions are the HTTP settings shared by every probe of a run.
+type clientOptions struct {
+	rps     float64 // overall requests per second, 0 = unlimited
+	proxy   string
+	headers []string // "Name: value"
+	hosts   []string // targets or URLs the headers are sent to
+	har     *har.Recorder
+}
+
+// newClient shares one connection pool across targets, throttled to rps
+// requests per second overall when rps > 0.
+func newClient(o clientOptions) (*http.Client, error) {
+	var rt http.RoundTripper = http.DefaultTransport
+	if o.proxy != "" {
+		u, err := neturl.Parse(o.proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid --proxy %q", o.proxy)
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(u)
+		rt = t
+	}
+	if o.har != nil {
+		// Innermost, so the recording shows the headers added below.
+		rt = o.har.Transport(rt)
+	}
+
+	header := http.Header{}
+	for _, h := range o.headers {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --header %q (want 'Name: value')", h)
+		}
+		header.Add(name, strings.TrimSpace(value))
+	}
+	if len(header) > 0 {
+		hosts := map[string]bool{}
+		for _, h := range o.hosts {
+			if u, err := neturl.Parse(scanner.BaseURL(h)); err == nil {
+				hosts[strings.ToLower(u.Hostname())] = true
+			}
+		}
+		rt = headerTransport{base: rt, header: header, hosts: hosts}
+	}
+
+	if o.rps > 0 {
+		burst := int(o.rps)
+		if burst < 1 {
+			burst = 1
+		}
+		rt = budgetTransport{base: rt, limiter: rate.NewLimiter(rate.Limit(o.rps), burst)}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// applyProfile fills every scan flag the user didn't pass from the --profile,
+// or from the config file's default profile.
+func applyProfile(c *cli.Context) error {
+	path := c.String("config")
+	if path == "" {
+		path = profile.Find()
+	}
+	if path == "" {
+		if c.IsSet("profile") {
+			return fmt.Errorf("--profile %s: no config file (looked for %s)", c.String("profile"), strings.Join(profile.Candidates(), ", "))
+		}
+		return nil
+	}
+	f, err := profile.Load(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	p, err := f.Profile(c.String("profile"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, set := range p.Settings() {
+		if c.IsSet(set.Flag) {
+			continue
+		}
+		if err := c.Set(set.Flag, set.Value); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, set.Flag, err)
+		}
+	}
+	return nil
+}
+
+func runScan(c *cli.Context) error {
+	out, errOut := c.App.Writer, c.App.ErrWriter
+	if err := applyProfile(c); err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	url := c.String("url")
+	only200 := c.Bool("only200")
+	file := c.String("file")
+	concurrency := c.Int("concurrency")
+	jsonFile := c.String("json")
+	jsonStdout := c.Bool("json-stdout")
+	tech := c.StringSlice("tech")
+	format, output := reportFormat(c.String("format"), c.String("output"))
+	if format != "" {
+		if _, ok := export.Lookup(format); !ok {
+			return cli.Exit("unknown --format "+format+" (want one of "+strings.Join(export.Formats(), ", ")+")", policy.ExitUsage)
+		}
+	}
+	ndjson := c.Bool("ndjson")
+	if ndjson && (jsonStdout || (format != "" && output == "")) {
+		return cli.Exit("--ndjson can't share stdout with --json-stdout or --format without --output", policy.ExitUsage)
+	}
+	// A report on stdout replaces the normal output, like --json-stdout.
+	quiet := jsonStdout || ndjson || (format != "" && output == "")
+	useTUI := c.Bool("tui")
+	if useTUI && quiet {
+		return cli.Exit("--tui needs the terminal: write reports with --output or --json instead", policy.ExitUsage)
+	}
+	sarifFile := c.String("sarif")
+	var analyze []scanner.AnalyzerConfig
+	for _, name := range c.StringSlice("analyze") {
+		a, err := analyzers.Builtin(name)
+		if err != nil {
+			return cli.Exit("--analyze: "+err.Error(), policy.ExitUsage)
+		}
+		analyze = append(analyze, scanner.AnalyzerConfig{Analyzer: a})
+	}
+	pol, err := policy.Parse(c.StringSlice("fail-on"))
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+
+	robotsFile, base := c.String("robots-file"), c.String("base")
+	noProbe := c.Bool("no-probe")
+	switch {
+	case base != "" && robotsFile == "":
+		return cli.Exit("--base needs --robots-file", policy.ExitUsage)
+	case robotsFile != "" && (url != "" || file != ""):
+		return cli.Exit("--robots-file takes its target from --base, not --url or --file", policy.ExitUsage)
+	case robotsFile != "" && base == "" && !noProbe:
+		return cli.Exit("--robots-file needs --base to probe against, or --no-probe", policy.ExitUsage)
+	case noProbe && c.String("state") != "":
+		return cli.Exit("--no-probe has nothing to checkpoint in --state", policy.ExitUsage)
+	}
+
+	if url == "" && file == "" && robotsFile == "" {
+		logo.Fprint(out)
+		showHelp(c)
+		return nil
+	}
+
+	var robots []byte
+	if robotsFile == "-" {
+		robots, err = io.ReadAll(os.Stdin)
+		robotsFile = "stdin" // as reports name it
+	} else if robotsFile != "" {
+		robots, err = os.ReadFile(robotsFile)
+	}
+	if err != nil {
+		return cli.Exit("--robots-file: "+err.Error(), policy.ExitUsage)
+	}
+
+	var urls []string
+	if file != "" {
+		list, skipped, err := targets.Load(file, targets.Options{ExpandCIDR: c.Bool("expand-cidr")})
+		if err != nil {
+			return cli.Exit("--file: "+err.Error(), policy.ExitUsage)
+		}
+		for _, e := range skipped {
+			fmt.Fprintln(errOut, colors.YELLOW+"[!] Skipping "+file+" "+e.Error()+colors.ENDC)
+		}
+		urls = list
+	}
+
+	if url != "" {
+		t, err := targets.Parse(url)
+		if err != nil {
+			return cli.Exit("invalid --url: "+err.Error(), policy.ExitUsage)
+		}
+		if !slices.Contains(urls, t) {
+			urls = append(urls, t)
+		}
+	}
+	if base != "" {
+		t, err := targets.Parse(base)
+		if err != nil {
+			return cli.Exit("invalid --base: "+err.Error(), policy.ExitUsage)
+		}
+		urls = append(urls, t)
+	}
+
+	if !quiet {
+		logo.Fprint(out)
+	}
+
+	scope, err := targets.NewScope(c.StringSlice("scope"), c.StringSlice("out-of-scope"))
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	inScope := urls[:0]
+	for _, t := range urls {
+		if scope.Allows(t) {
+			inScope = append(inScope, t)
+		} else {
+			fmt.Fprintln(errOut, colors.YELLOW+"[!] Skipping out-of-scope target "+t+colors.ENDC)
+		}
+	}
+	if len(inScope) == 0 && len(urls) > 0 {
+		return cli.Exit("no targets in scope", policy.ExitUsage)
+	}
+	urls = inScope
+	if robotsFile != "" && base == "" {
+		// Nothing is sent, so the file itself stands in for the target.
+		urls = []string{robotsFile}
+	}
+
+	var recorder *har.Recorder
+	harPath := c.String("har")
+	if harPath != "" {
+		// Whatever --header and --host-header send is as secret as a cookie.
+		recorder = har.NewRecorder(0)
+		recorder.Redact = append(recorder.Redact, scanner.HostHeaderNames(c.StringSlice("host-header"))...)
+		for _, h := range c.StringSlice("header") {
+			name, _, _ := strings.Cut(h, ":")
+			recorder.Redact = append(recorder.Redact, strings.TrimSpace(name))
+		}
+	}
+	client, err := newClient(clientOptions{
+		rps:     c.Float64("max-rps"),
+		proxy:   c.String("proxy"),
+		headers: c.StringSlice("header"),
+		hosts:   urls,
+		har:     recorder,
+	})
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+
+	cfg := scanConfig{
+		client: client,
+		opts: scanner.Options{
+			Only200:     only200,
+			SearchBing:  c.Bool("search-disallow"),
+			Archive:     c.Bool("archive") || c.String("archive-url") != "",
+			ArchiveURL:  c.String("archive-url"),
+			Concurrency: concurrency,
+
+			Fingerprint: c.Bool("fingerprint") || len(tech) > 0,
+			InspectBody: c.Bool("inspect"),
+			Analyzers:   analyze,
+			UserAgent:   c.String("user-agent"),
+
+			ArchiveVersions: c.Int("archive-versions"),
+			RequestTimeout:  c.Duration("timeout"),
+
+			Adaptive:       c.Bool("adaptive"),
+			MinConcurrency: c.Int("min-concurrency"),
+			MaxConcurrency: c.Int("max-concurrency"),
+		},
+		tech:   tech,
+		policy: pol,
+
+		robotsFile: robotsFile,
+		robots:     robots,
+		noProbe:    noProbe,
+	}
+	if !useTUI {
+		cfg.warn = errOut
+	}
+	if ndjson {
+		cfg.stream = export.NewNDJSON(out)
+	}
+	if specs := c.StringSlice("host-header"); len(specs) > 0 {
+		hook, err := scanner.HostHeaders(specs)
+		if err != nil {
+			return cli.Exit("--host-header: "+err.Error(), policy.ExitUsage)
+		}
+		cfg.hooks = append(cfg.hooks, scanner.OnRequest(hook))
+	}
+	if path := c.String("trace"); path != "" {
+		trace, err := openTrace(path, errOut)
+		if err != nil {
+			return cli.Exit("--trace: "+err.Error(), policy.ExitUsage)
+		}
+		defer trace.Close()
+		cfg.hooks = append(cfg.hooks, scanner.OnResponse(trace.hook))
+	}
+
+	ctx := context.Background()
+	statePath := c.String("state")
+	switch {
+	case statePath == "" && c.Bool("resume"):
+		return cli.Exit("--resume needs --state", policy.ExitUsage)
+	case c.Bool("resume"):
+		if cfg.resume, err = state.Load(statePath); err != nil {
+			return cli.Exit("can't resume: "+err.Error(), policy.ExitUsage)
+		}
+		finished := 0
+		for _, u := range urls {
+			if t := cfg.resume[u]; t != nil && t.Done != nil {
+				finished++
+			}
+		}
+		fmt.Fprintf(errOut, "%s[*] Resuming from %s: %d of %d targets already done%s\n", colors.YELLOW, statePath, finished, len(urls), colors.ENDC)
+		cfg.state, err = state.Append(statePath)
+	case statePath != "":
+		cfg.state, err = state.Create(statePath)
+	}
+	if err != nil {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	if cfg.state != nil {
+		// Stop cleanly on Ctrl-C so every finished probe is checkpointed.
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+
+	var (
+		runs         = make([]targetRun, 0, len(urls))
+		sarifTargets []sarif.Target
+		interrupted  int
+		cancelled    int
+		report       io.Writer = out
+		deferred     bytes.Buffer
+	)
+	if useTUI {
+		// The view owns the terminal; reports print once it closes.
+		report = &deferred
+	}
+	ctx, stopAll := context.WithCancel(ctx)
+	defer stopAll()
+	scan := func(u string) targetRun {
+		tctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		cfg.ui.Started(u, cancel)
+		run := scanTarget(tctx, cfg, u)
+		cfg.ui.Finished(u, run.Err, run.Interrupted)
+		return run
+	}
+	runAll := func() {
+		scanTargets(urls, c.Int("target-concurrency"), !c.Bool("unordered"), scan, func(_ int, run targetRun) {
+			if run.Interrupted {
+				if ctx.Err() == nil {
+					cancelled++ // from the TUI
+				} else {
+					interrupted++
+				}
+				return
+			}
+			runs = append(runs, run)
+			if !quiet {
+				var buf bytes.Buffer
+				printRun(&buf, run, only200, concurrency)
+				report.Write(buf.Bytes())
+			}
+			if sarifFile != "" && run.Err == nil {
+				sarifTargets = append(sarifTargets, sarif.Target{Name: run.Target, Results: run.Results})
+			}
+			// Files get one report merged from every target once all are
+			// done; stdout gets each target's as it finishes.
+			if !jsonStdout && (format == "" || output != "") && cfg.stream == nil {
+				return
+			}
+
+			scanResult := run.export(only200)
+			if cfg.stream != nil {
+				cfg.stream.Summary(scanResult, run.Err)
+			}
+
+			if jsonStdout {
+				jsonStr, err := export.ToJSON(scanResult)
+				if err != nil {
+					fmt.Fprintln(report, colors.FAIL+"Error creating JSON output: "+err.Error()+colors.ENDC)
+				} else {
+					fmt.Fprintln(report, jsonStr)
+				}
+			}
+
+			if format != "" && output == "" {
+				if err := export.Write(report, format, scanResult); err != nil {
+					fmt.Fprintln(report, colors.FAIL+"Error writing report: "+err.Error()+colors.ENDC)
+				}
+			}
+		})
+	}
+
+	if useTUI {
+		done := make(chan struct{})
+		err := tui.Run(out, urls, stopAll, func(feed *tui.Feed) {
+			cfg.ui = feed
+			runAll()
+			close(done)
+		})
+		if err != nil {
+			stopAll()
+			<-done
+			return cli.Exit("tui: "+err.Error(), 1)
+		}
+		<-done
+		out.Write(deferred.Bytes())
+	} else {
+		runAll()
+	}
+
+	if !quiet && len(runs) > 1 {
+		printSummary(out, runs)
+	}
+
+	if (jsonFile != "" || output != "") && len(runs) > 0 {
+		// Written even when interrupted, like the HAR: --resume rewrites it
+		// with every target once the rest are done.
+		reports := make([]export.ScanResult, 0, len(runs))
+		for _, run := range runs {
+			reports = append(reports, run.export(only200))
+		}
+		merged := export.Merge(reports)
+		if jsonFile != "" {
+			if err := export.SaveToFile(merged, jsonFile); err != nil {
+				fmt.Fprintln(errOut, colors.FAIL+"Error saving JSON to file: "+err.Error()+colors.ENDC)
+			} else if !quiet {
+				fmt.Fprintln(out, colors.OKGREEN+"Results exported to "+jsonFile+colors.ENDC)
+			}
+		}
+		if output != "" {
+			if err := export.WriteFile(output, format, merged); err != nil {
+				fmt.Fprintln(errOut, colors.FAIL+"Error saving report: "+err.Error()+colors.ENDC)
+			} else if !quiet {
+				fmt.Fprintln(out, colors.OKGREEN+"Report written to "+output+colors.ENDC)
+			}
+		}
+	}
+	if recorder != nil {
+		// Written even when interrupted: a partial recording is still evidence.
+		if err := writeHAR(harPath, recorder); err != nil {
+			fmt.Fprintln(errOut, colors.FAIL+"Error writing HAR file: "+err.Error()+colors.ENDC)
+		} else if !quiet {
+			fmt.Fprintf(out, "%sHAR with %d requests written to %s%s\n", colors.OKGREEN, recorder.Len(), harPath, colors.ENDC)
+		}
+	}
+	if cfg.state != nil {
+		if err := cfg.state.Close(); err != nil {
+			fmt.Fprintln(errOut, colors.FAIL+"Error writing state file: "+err.Error()+colors.ENDC)
+		}
+	}
+	if cancelled > 0 {
+		fmt.Fprintf(errOut, "%s[!] %d target(s) cancelled and left out of the report%s\n", colors.YELLOW, cancelled, colors.ENDC)
+	}
+	if interrupted > 0 {
+		hint := ""
+		if statePath != "" {
+			hint = "; rerun with --state " + statePath + " --resume to continue"
+		}
+		fmt.Fprintf(errOut, "%s[!] Interrupted with %d target(s) unfinished%s%s\n", colors.YELLOW, interrupted, hint, colors.ENDC)
+		return cli.Exit("", exitInterrupted)
+	}
+
+	// Without --fail-on, scan errors and missing robots.txt files still set
+	// the exit code; only findings need a rule.
+	decisions := make([]types.PolicyDecision, 0, len(runs))
+	for _, run := range runs {
+		if run.Decision != nil {
+			decisions = append(decisions, *run.Decision)
+		} else {
+			decisions = append(decisions, types.PolicyDecision{ExitCode: policy.ScanExit(run.policyInput())})
+		}
+	}
+	exitCode := policy.Combine(decisions)
+
+	if sarifFile != "" {
+		if err := writeSARIF(sarifFile, sarifTargets); err != nil {
+			fmt.Fprintln(errOut, colors.FAIL+"Error saving SARIF: "+err.Error()+colors.ENDC)
+		} else if !quiet {
+			fmt.Fprintln(out, colors.OKGREEN+"SARIF written to "+sarifFile+colors.ENDC)
+		}
+	}
+	if exitCode != policy.ExitOK {
+		return cli.Exit("", exitCode)
+	}
+	return nil
+}
+
+// printRun writes one target's human-readable report; targets scanned in
+// parallel are buffered through it so their lines don't interleave.
+func printRun(w io.Writer, run targetRun, only200 bool, concurrency int) {
+	printDate(w, run.Target, run.Started)
+	switch {
+	case run.Err != nil:
+		fmt.Fprintln(w, colors.FAIL+run.Err.Error()+colors.ENDC)
+	case run.NoProbe:
+		fmt.Fprintf(w, "Found %d Disallow entries (not probed):\n", len(run.Disallow))
+		for _, p := range run.Disallow {
+			fmt.Fprintln(w, "/"+p)
+		}
+		writeLint(w, "text", run.RobotsSource, run.Lint)
+	case len(run.Disallow) == 0:
+		fmt.Fprintln(w, colors.YELLOW+"No Disallow entries found in robots.txt."+colors.ENDC)
+		printResults(w, run.Results, only200) // archived entries, if any
+	default:
+		fmt.Fprintf(w, "Found %d Disallow entries. Processing with %d workers...\n", len(run.Disallow), concurrency)
+		printResults(w, run.Results, only200)
+		if p := run.Profile; p != nil && p.Mode == "adaptive" {
+			fmt.Fprintf(w, "Adaptive concurrency: %d -> %d (peak %d, mean %.1f, %d backoffs)\n",
+				p.Initial, p.Final, p.Peak, p.Mean, p.Decreases)
+		}
+	}
+	if run.Quality.Degraded {
+		fmt.Fprintln(w, colors.YELLOW+"[!] Scan degraded: "+run.Quality.Reason+colors.ENDC)
+	}
+	if d := run.Decision; d != nil {
+		printDecision(w, *d)
+	}
+	fmt.Fprintf(w, "\nFinished in %.2f seconds.\n", run.Duration.Seconds())
+}
+
+func printDecision(w io.Writer, d types.PolicyDecision) {
+	for _, r := range d.Rules {
+		if r.Triggered {
+			fmt.Fprintf(w, "%s[!] Policy %q failed: %d matching paths (threshold %d)%s\n", colors.FAIL, r.Rule, r.Matches, r.Threshold, colors.ENDC)
+		}
+	}
+	switch d.Outcome {
+	case policy.OutcomePass:
+		fmt.Fprintln(w, colors.OKGREEN+"[+] Policy passed"+colors.ENDC)
+	case policy.OutcomeScanError, policy.OutcomeNoRobots:
+		msg := "[!] Policy failed: " + d.Outcome
+		if d.Reason != "" {
+			msg += " (" + d.Reason + ")"
+		}
+		fmt.Fprintln(w, colors.YELLOW+msg+colors.ENDC)
+	}
+}
+
+// printSummary closes a multi-target run with one row per target.
+func printSummary(w io.Writer, runs []targetRun) {
+	fmt.Fprintln(w, "\nSummary:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tPATHS\t200\tOTHER\tERRORS\tSTATE\tTIME")
+	for _, run := range runs {
+		sr := run.export(false)
+		state := "ok"
+		switch {
+		case run.Err != nil:
+			state = "failed"
+		case run.NoProbe:
+			state = "not probed"
+		case run.Quality.Degraded:
+			state = "degraded"
+		case len(run.Disallow) == 0:
+			state = "no disallow"
+		}
+		if run.Decision != nil {
+			state += " / " + run.Decision.Outcome
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t%.2fs\n",
+			run.Target, len(run.Disallow), sr.Status200, sr.OtherStatus, sr.Errors, state, run.Duration.Seconds())
+	}
+	tw.Flush()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+// traceLine is one HTTP exchange in a --trace log.
+type traceLine struct {
+	Time       string  `json:"time"`
+	Kind       string  `json:"kind"` // robots, probe or search
+	Method     string  `json:"method"`
+	URL        string  `json:"url"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	ElapsedMS  float64 `json:"elapsed_ms"`
+}
+
+// traceLog writes every request the scanner sends as an NDJSON line. It is
+// shared by all targets, so writes are serialised.
+type traceLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	c   io.Closer // nil for stderr
+}
+
+// openTrace opens path for --trace; "-" means errOut.
+func openTrace(path string, errOut io.Writer) (*traceLog, error) {
+	if path == "-" {
+		return &traceLog{enc: json.NewEncoder(errOut)}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &traceLog{enc: json.NewEncoder(f), c: f}, nil
+}
+
+func (t *traceLog) hook(ex scanner.Exchange) {
+	line := traceLine{
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Kind:      ex.Kind,
+		Method:    ex.Request.Method,
+		URL:       ex.Request.URL.String(),
+		ElapsedMS: float64(ex.Duration.Microseconds()) / 1000,
+	}
+	if ex.Err != nil {
+		line.Error = ex.Err.Error()
+	} else {
+		line.StatusCode = ex.Response.StatusCode
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enc.Encode(line)
+}
+
+func (t *traceLog) Close() error {
+	if t.c == nil {
+		return nil
+	}
+	return t.c.Close()
+}
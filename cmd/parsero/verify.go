@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zvdy/parsero-go/internal/policy"
+	"github.com/zvdy/parsero-go/internal/targets"
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/export"
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+func verifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Fail when a target's exposure drifts from a committed baseline",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "baseline",
+				Aliases:  []string{"b"},
+				Usage:    "Baseline file (a parsero JSON export)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "Target to scan (default: the baseline's target)",
+			},
+			&cli.BoolFlag{
+				Name:  "update-baseline",
+				Usage: "Rewrite the baseline from a fresh scan instead of comparing",
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Aliases: []string{"c"},
+				Usage:   "Number of concurrent workers",
+				Value:   runtime.NumCPU(),
+			},
+			&cli.BoolFlag{
+				Name:  "inspect",
+				Usage: "Fetch response bodies to recognise login forms and WAF block pages",
+			},
+		},
+		Action: runVerify,
+	}
+}
+
+func runVerify(c *cli.Context) error {
+	path := c.String("baseline")
+	update := c.Bool("update-baseline")
+
+	baseline, err := export.LoadFile(path)
+	if err != nil && !(update && errors.Is(err, os.ErrNotExist)) {
+		return cli.Exit(err.Error(), policy.ExitUsage)
+	}
+	target := baseline.URL
+	if u := c.String("url"); u != "" {
+		if target, err = targets.Parse(u); err != nil {
+			return cli.Exit("invalid --url: "+err.Error(), policy.ExitUsage)
+		}
+	}
+	if target == "" {
+		return cli.Exit("no target: pass --url or a baseline that names one", policy.ExitUsage)
+	}
+
+	cfg := scanConfig{
+		client: http.DefaultClient,
+		opts: scanner.Options{
+			Concurrency: c.Int("concurrency"),
+			InspectBody: c.Bool("inspect"),
+		},
+	}
+	run := scanTarget(context.Background(), cfg, target)
+	switch {
+	case run.Err != nil:
+		return cli.Exit(run.Err.Error(), policy.ExitScanError)
+	case run.RobotsStatus == http.StatusNotFound:
+		return cli.Exit(scanner.ErrNoRobots.Error(), policy.ExitNoRobots)
+	}
+
+	if update {
+		if err := export.SaveToFile(snapshot(run), path); err != nil {
+			return cli.Exit(err.Error(), policy.ExitScanError)
+		}
+		fmt.Fprintf(c.App.Writer, "%sBaseline %s updated: %d Disallow entries, %d reachable%s\n",
+			colors.OKGREEN, path, len(run.Disallow), countReachable(run), colors.ENDC)
+		return nil
+	}
+
+	d := computeDrift(baseline, run.export(false))
+	if d.empty() {
+		fmt.Fprintln(c.App.Writer, colors.OKGREEN+"[+] "+target+" matches "+path+colors.ENDC)
+		return nil
+	}
+	printDrift(c.App.Writer, path+" (baseline)", target+" (current)", d)
+	if !d.failing() {
+		return nil
+	}
+	fmt.Fprintln(c.App.Writer, colors.FAIL+"[-] Exposure drifted from the baseline; rerun with --update-baseline if this is expected"+colors.ENDC)
+	if run.Quality.Degraded {
+		fmt.Fprintln(c.App.Writer, colors.YELLOW+"[!] Scan degraded, drift may be spurious: "+run.Quality.Reason+colors.ENDC)
+	}
+	return cli.Exit("", policy.ExitFindings)
+}
+
+// snapshot is the baseline form of a run: volatile fields (timestamp,
+// duration, concurrency) cleared and results sorted, so regenerating an
+// unchanged baseline produces no diff in version control.
+func snapshot(run targetRun) export.ScanResult {
+	sr := run.export(false)
+	sr.Timestamp, sr.Duration, sr.Concurrency = "", 0, nil
+	sort.Slice(sr.Results, func(i, j int) bool { return sr.Results[i].URL < sr.Results[j].URL })
+	sort.Strings(sr.Disallow)
+	return sr
+}
+
+func countReachable(run targetRun) int {
+	n := 0
+	for _, r := range run.Results {
+		if r.Error == nil && classify.Reachable(r.Class, r.StatusCode) {
+			n++
+		}
+	}
+	return n
+}
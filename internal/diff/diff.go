@@ -1,31 +1,62 @@
 // Package diff compares two scans of the same target to surface security-
 // relevant changes — chiefly Disallow paths that have *become reachable*
-// (HTTP 200) since the previous scan, which is exactly the regression a
-// recurring monitor should alert on.
+// (classified "open", see pkg/classify) since the previous scan, which is
+// exactly the regression a recurring monitor should alert on.
 package diff
 
-import "sort"
+import (
+	"sort"
+
+	"github.com/zvdy/parsero-go/internal/fingerprint"
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
 
 type Probe struct {
-	URL        string
-	StatusCode int
+	URL          string
+	StatusCode   int
+	Class        string // empty for legacy rows; derived from StatusCode then
+	Fingerprints []string
+}
+
+// FromResults converts scanner results to probes.
+func FromResults(results []types.Result) []Probe {
+	out := make([]Probe, 0, len(results))
+	for _, r := range results {
+		out = append(out, Probe{URL: r.URL, StatusCode: r.StatusCode, Class: r.Class, Fingerprints: r.Fingerprints})
+	}
+	return out
 }
 
 type Result struct {
-	NewlyReachable    []string // 200 now, wasn't before — the alertable set
-	NoLongerReachable []string // 200 before, isn't now
+	NewlyReachable    []string // open now, wasn't before — the alertable set
+	NoLongerReachable []string // open before, isn't now
+	// StatusChanged lists paths probed in both scans whose status code or
+	// class moved, reachable or not (e.g. 403 -> 404).
+	StatusChanged []StatusChange
+}
+
+type StatusChange struct {
+	URL       string
+	From, To  int
+	FromClass string
+	ToClass   string
 }
 
+// HasChanges reports reachability changes — what monitors alert on. Status
+// moves between unreachable states don't count.
 func (r Result) HasChanges() bool {
 	return len(r.NewlyReachable) > 0 || len(r.NoLongerReachable) > 0
 }
 
-// Compute diffs the 200-reachable sets of prev and cur; output is sorted.
+// Compute diffs the reachable sets of prev and cur; output is sorted. A 200
+// login form or WAF page doesn't count as reachable.
 func Compute(prev, cur []Probe) Result {
 	prevOK := reachableSet(prev)
 	curOK := reachableSet(cur)
 
 	var res Result
+	res.StatusChanged = statusChanges(prev, cur)
 	for url := range curOK {
 		if !prevOK[url] {
 			res.NewlyReachable = append(res.NewlyReachable, url)
@@ -41,10 +72,71 @@ func Compute(prev, cur []Probe) Result {
 	return res
 }
 
+// WithFingerprints narrows r to newly reachable URLs whose current probe
+// carries one of names — "alert when any Jenkins becomes reachable". The
+// no-longer-reachable side is dropped: a vanished service has no fingerprint.
+func (r Result) WithFingerprints(cur []Probe, names []string) Result {
+	fps := make(map[string][]string, len(cur))
+	for _, p := range cur {
+		fps[p.URL] = p.Fingerprints
+	}
+	var out Result
+	for _, url := range r.NewlyReachable {
+		if fingerprint.Matches(fps[url], names) {
+			out.NewlyReachable = append(out.NewlyReachable, url)
+		}
+	}
+	return out
+}
+
+func statusChanges(prev, cur []Probe) []StatusChange {
+	before := make(map[string]Probe, len(prev))
+	for _, p := range prev {
+		before[p.URL] = p
+	}
+	var out []StatusChange
+	for _, c := range cur {
+		p, ok := before[c.URL]
+		if !ok {
+			continue
+		}
+		fromClass := classify.Effective(p.Class, p.StatusCode)
+		toClass := classify.Effective(c.Class, c.StatusCode)
+		if p.StatusCode != c.StatusCode || fromClass != toClass {
+			out = append(out, StatusChange{URL: c.URL, From: p.StatusCode, To: c.StatusCode, FromClass: fromClass, ToClass: toClass})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].URL < out[j].URL })
+	return out
+}
+
+// Entries diffs two robots.txt Disallow lists; output is sorted.
+func Entries(prev, cur []string) (added, removed []string) {
+	before := make(map[string]bool, len(prev))
+	for _, e := range prev {
+		before[e] = true
+	}
+	after := make(map[string]bool, len(cur))
+	for _, e := range cur {
+		if !before[e] && !after[e] {
+			added = append(added, e)
+		}
+		after[e] = true
+	}
+	for e := range before {
+		if !after[e] {
+			removed = append(removed, e)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
 func reachableSet(probes []Probe) map[string]bool {
 	set := make(map[string]bool, len(probes))
 	for _, p := range probes {
-		if p.StatusCode == 200 {
+		if classify.Reachable(p.Class, p.StatusCode) {
 			set[p.URL] = true
 		}
 	}
@@ -41,6 +41,14 @@ func TestComputeNoLongerReachable(t *testing.T) {
 	}
 }
 
+func TestComputeIgnoresLoginPages(t *testing.T) {
+	prev := []Probe{{URL: "http://x/admin", StatusCode: 403, Class: "forbidden"}}
+	cur := []Probe{{URL: "http://x/admin", StatusCode: 200, Class: "login"}}
+	if got := Compute(prev, cur); got.HasChanges() {
+		t.Errorf("a 200 login page should not be newly reachable, got %+v", got)
+	}
+}
+
 func TestComputeNoChange(t *testing.T) {
 	probes := []Probe{
 		{URL: "http://x/a", StatusCode: 200},
@@ -51,3 +59,48 @@ func TestComputeNoChange(t *testing.T) {
 		t.Errorf("expected no changes, got %+v", got)
 	}
 }
+
+func TestWithFingerprints(t *testing.T) {
+	prev := []Probe{{URL: "http://x/ci", StatusCode: 403}}
+	cur := []Probe{
+		{URL: "http://x/ci", StatusCode: 200, Fingerprints: []string{"jenkins"}},
+		{URL: "http://x/blog", StatusCode: 200, Fingerprints: []string{"wordpress"}},
+	}
+	got := Compute(prev, cur).WithFingerprints(cur, []string{"Jenkins"})
+	want := []string{"http://x/ci"}
+	if !reflect.DeepEqual(got.NewlyReachable, want) {
+		t.Errorf("NewlyReachable = %v, want %v", got.NewlyReachable, want)
+	}
+	if got := Compute(prev, cur).WithFingerprints(cur, []string{"grafana"}); got.HasChanges() {
+		t.Errorf("expected no matching changes, got %+v", got)
+	}
+}
+
+func TestComputeStatusChanged(t *testing.T) {
+	prev := []Probe{
+		{URL: "http://x/a", StatusCode: 403},
+		{URL: "http://x/b", StatusCode: 200},
+		{URL: "http://x/c", StatusCode: 200, Class: "open"},
+	}
+	cur := []Probe{
+		{URL: "http://x/a", StatusCode: 404},                 // unreachable either way
+		{URL: "http://x/b", StatusCode: 200},                 // unchanged
+		{URL: "http://x/c", StatusCode: 200, Class: "login"}, // same status, new class
+		{URL: "http://x/d", StatusCode: 200},                 // new, not a change
+	}
+	got := Compute(prev, cur)
+	want := []StatusChange{
+		{URL: "http://x/a", From: 403, To: 404, FromClass: "forbidden", ToClass: "not_found"},
+		{URL: "http://x/c", From: 200, To: 200, FromClass: "open", ToClass: "login"},
+	}
+	if !reflect.DeepEqual(got.StatusChanged, want) {
+		t.Errorf("StatusChanged = %+v, want %+v", got.StatusChanged, want)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	added, removed := Entries([]string{"admin", "tmp"}, []string{"tmp", "backup", "backup", "api"})
+	if !reflect.DeepEqual(added, []string{"api", "backup"}) || !reflect.DeepEqual(removed, []string{"admin"}) {
+		t.Errorf("Entries = %v, %v", added, removed)
+	}
+}
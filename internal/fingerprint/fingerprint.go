@@ -0,0 +1,310 @@
+// Package fingerprint identifies the technology behind an HTTP response by
+// matching headers, cookies, HTML meta tags and favicon hashes against an
+// embedded signature database. It tells a phpMyAdmin login apart from a
+// WordPress admin or a Jenkins console, which a bare status code can't.
+package fingerprint
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//go:embed signatures.json
+var signaturesJSON []byte
+
+// Signature describes one technology. Any single matcher hitting is enough; a
+// header or meta pattern of "" only requires presence.
+type Signature struct {
+	Name    string            `json:"name"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies []string          `json:"cookies,omitempty"` // cookie-name prefixes
+	Meta    map[string]string `json:"meta,omitempty"`    // <meta name|property> -> content pattern
+	Body    []string          `json:"body,omitempty"`
+	Favicon []int32           `json:"favicon,omitempty"` // Shodan-style mmh3 hashes
+}
+
+// Response is what a probe saw. Body is expected to be capped by the caller;
+// Favicon is nil when no icon was fetched.
+type Response struct {
+	Header  http.Header
+	Body    []byte
+	Favicon *int32
+}
+
+type compiled struct {
+	name    string
+	headers map[string]*regexp.Regexp
+	cookies []string
+	meta    map[string]*regexp.Regexp
+	body    []*regexp.Regexp
+	favicon map[int32]bool
+}
+
+// DB is an immutable, compiled signature set; safe for concurrent use.
+type DB struct {
+	sigs []compiled
+}
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *DB
+)
+
+// Default returns the embedded signature database.
+func Default() *DB {
+	defaultOnce.Do(func() {
+		db, err := Load(bytes.NewReader(signaturesJSON))
+		if err != nil {
+			panic("fingerprint: embedded signatures: " + err.Error())
+		}
+		defaultDB = db
+	})
+	return defaultDB
+}
+
+// Load compiles a JSON array of Signatures.
+func Load(r io.Reader) (*DB, error) {
+	var sigs []Signature
+	if err := json.NewDecoder(r).Decode(&sigs); err != nil {
+		return nil, fmt.Errorf("decode signatures: %w", err)
+	}
+	db := &DB{sigs: make([]compiled, 0, len(sigs))}
+	for _, s := range sigs {
+		c := compiled{
+			name:    s.Name,
+			headers: map[string]*regexp.Regexp{},
+			cookies: s.Cookies,
+			meta:    map[string]*regexp.Regexp{},
+			favicon: map[int32]bool{},
+		}
+		for h, pat := range s.Headers {
+			re, err := compilePattern(pat)
+			if err != nil {
+				return nil, fmt.Errorf("%s: header %s: %w", s.Name, h, err)
+			}
+			c.headers[http.CanonicalHeaderKey(h)] = re
+		}
+		for m, pat := range s.Meta {
+			re, err := compilePattern(pat)
+			if err != nil {
+				return nil, fmt.Errorf("%s: meta %s: %w", s.Name, m, err)
+			}
+			c.meta[strings.ToLower(m)] = re
+		}
+		for _, pat := range s.Body {
+			re, err := regexp.Compile(pat)
+			if err != nil {
+				return nil, fmt.Errorf("%s: body: %w", s.Name, err)
+			}
+			c.body = append(c.body, re)
+		}
+		for _, h := range s.Favicon {
+			c.favicon[h] = true
+		}
+		db.sigs = append(db.sigs, c)
+	}
+	return db, nil
+}
+
+// compilePattern treats "" as "present" and everything else as a
+// case-insensitive regexp.
+func compilePattern(pat string) (*regexp.Regexp, error) {
+	if pat == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + pat)
+}
+
+// Names lists every technology the database knows, sorted.
+func (db *DB) Names() []string {
+	out := make([]string, 0, len(db.sigs))
+	for _, s := range db.sigs {
+		out = append(out, s.name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Match returns the sorted names of every signature that hits resp.
+func (db *DB) Match(resp Response) []string {
+	meta := metaTags(resp.Body)
+	cookies := cookieNames(resp.Header)
+
+	var out []string
+	for _, s := range db.sigs {
+		if s.matches(resp, meta, cookies) {
+			out = append(out, s.name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (s compiled) matches(resp Response, meta map[string]string, cookies []string) bool {
+	for h, re := range s.headers {
+		vals, ok := resp.Header[h]
+		if !ok {
+			continue
+		}
+		if re == nil {
+			return true
+		}
+		for _, v := range vals {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	for _, prefix := range s.cookies {
+		for _, c := range cookies {
+			if strings.HasPrefix(c, prefix) {
+				return true
+			}
+		}
+	}
+	for name, re := range s.meta {
+		content, ok := meta[name]
+		if ok && (re == nil || re.MatchString(content)) {
+			return true
+		}
+	}
+	for _, re := range s.body {
+		if re.Match(resp.Body) {
+			return true
+		}
+	}
+	if resp.Favicon != nil && s.favicon[*resp.Favicon] {
+		return true
+	}
+	return false
+}
+
+func cookieNames(h http.Header) []string {
+	var out []string
+	for _, line := range h.Values("Set-Cookie") {
+		name, _, _ := strings.Cut(line, "=")
+		out = append(out, strings.TrimSpace(name))
+	}
+	return out
+}
+
+// metaTags maps lowercased <meta name=…> / <meta property=…> to content.
+func metaTags(body []byte) map[string]string {
+	out := map[string]string{}
+	if len(body) == 0 {
+		return out
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return out
+	}
+	doc.Find("meta").Each(func(_ int, sel *goquery.Selection) {
+		key := sel.AttrOr("name", sel.AttrOr("property", ""))
+		if key != "" {
+			out[strings.ToLower(key)] = sel.AttrOr("content", "")
+		}
+	})
+	return out
+}
+
+// IconHref returns the href of the page's first <link rel="icon">, or "".
+func IconHref(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var href string
+	doc.Find("link[href]").EachWithBreak(func(_ int, sel *goquery.Selection) bool {
+		for _, rel := range strings.Fields(strings.ToLower(sel.AttrOr("rel", ""))) {
+			if rel == "icon" {
+				href = sel.AttrOr("href", "")
+				return false
+			}
+		}
+		return true
+	})
+	return href
+}
+
+// FaviconHash computes the hash Shodan indexes as http.favicon.hash: mmh3 over
+// the MIME-style base64 of the icon (76-column lines, trailing newline).
+func FaviconHash(icon []byte) int32 {
+	enc := base64.StdEncoding.EncodeToString(icon)
+	var b strings.Builder
+	for len(enc) > 76 {
+		b.WriteString(enc[:76])
+		b.WriteByte('\n')
+		enc = enc[76:]
+	}
+	b.WriteString(enc)
+	b.WriteByte('\n')
+	return int32(murmur3([]byte(b.String()), 0))
+}
+
+// murmur3 is MurmurHash3 x86_32.
+func murmur3(data []byte, seed uint32) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	h := seed
+	n := len(data)
+	for i := 0; i+4 <= n; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+	var k uint32
+	tail := data[n&^3:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// Matches reports whether have contains any of want, case-insensitively. It is
+// the filter used by the CLI, the API and schedule alerts.
+func Matches(have, want []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package fingerprint
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Jenkins", "2.440")
+	h.Set("Server", "nginx/1.25")
+	got := Default().Match(Response{Header: h})
+	want := []string{"jenkins", "nginx"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match = %v, want %v", got, want)
+	}
+}
+
+func TestMatchCookieAndMeta(t *testing.T) {
+	h := http.Header{}
+	h.Add("Set-Cookie", "phpMyAdmin=abc; path=/")
+	if got := Default().Match(Response{Header: h}); !reflect.DeepEqual(got, []string{"phpmyadmin"}) {
+		t.Errorf("cookie match = %v", got)
+	}
+
+	body := []byte(`<html><head><meta name="generator" content="WordPress 6.4"></head></html>`)
+	if got := Default().Match(Response{Header: http.Header{}, Body: body}); !reflect.DeepEqual(got, []string{"wordpress"}) {
+		t.Errorf("meta match = %v", got)
+	}
+}
+
+func TestMatchFavicon(t *testing.T) {
+	hash := int32(81586312)
+	got := Default().Match(Response{Header: http.Header{}, Favicon: &hash})
+	if !reflect.DeepEqual(got, []string{"jenkins"}) {
+		t.Errorf("favicon match = %v", got)
+	}
+}
+
+func TestNoMatch(t *testing.T) {
+	if got := Default().Match(Response{Header: http.Header{}, Body: []byte("<p>hi</p>")}); len(got) != 0 {
+		t.Errorf("expected no match, got %v", got)
+	}
+}
+
+func TestLoadRejectsBadPattern(t *testing.T) {
+	if _, err := Load(strings.NewReader(`[{"name":"x","body":["("]}]`)); err == nil {
+		t.Error("expected error for invalid regexp")
+	}
+}
+
+func TestMurmur3(t *testing.T) {
+	if got := murmur3([]byte("hello"), 0); got != 613153351 {
+		t.Errorf("murmur3(hello) = %d, want 613153351", got)
+	}
+}
+
+func TestIconHref(t *testing.T) {
+	body := []byte(`<link rel="stylesheet" href="/a.css"><link rel="shortcut icon" href="/static/fav.ico">`)
+	if got := IconHref(body); got != "/static/fav.ico" {
+		t.Errorf("IconHref = %q", got)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	if !Matches([]string{"jenkins", "nginx"}, []string{"Jenkins"}) {
+		t.Error("expected case-insensitive match")
+	}
+	if Matches([]string{"nginx"}, []string{"jenkins"}) {
+		t.Error("unexpected match")
+	}
+}
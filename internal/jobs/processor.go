@@ -4,6 +4,8 @@
 package jobs
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"log"
 	"time"
@@ -12,10 +14,12 @@ import (
 	"github.com/zvdy/parsero-go/internal/config"
 	"github.com/zvdy/parsero-go/internal/diff"
 	"github.com/zvdy/parsero-go/internal/notify"
+	"github.com/zvdy/parsero-go/internal/quality"
 	"github.com/zvdy/parsero-go/internal/queue"
 	"github.com/zvdy/parsero-go/internal/safety"
-	"github.com/zvdy/parsero-go/internal/scanner"
 	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/har"
+	"github.com/zvdy/parsero-go/pkg/scanner"
 	"github.com/zvdy/parsero-go/pkg/types"
 )
 
@@ -94,24 +98,66 @@ func (p *Processor) Handle(ctx context.Context, scanID string) (err error) {
 	}
 
 	client := safety.GuardedClient(p.cfg.ScanTimeout)
-	s := scanner.New(client, scanner.Options{
-		Only200:     sc.Only200,
-		SearchBing:  sc.SearchBing && p.cfg.BingEnabled,
-		Concurrency: p.cfg.DefaultConcurrency,
-		MaxPaths:    p.cfg.MaxPaths,
-	})
-	s.SetRobotsCache(p.cache, p.cfg.RobotsCacheTTL)
-	s.OnProgress(func(done, total int) {
-		p.cache.SetProgress(runCtx, scanID, done, total)
-	})
+	var recorder *har.Recorder
+	if sc.RecordHAR {
+		recorder = har.NewRecorder(0)
+		recorder.Redact = append(recorder.Redact, scanner.HostHeaderNames(p.cfg.HostHeaders)...)
+		client = recorder.Client(client)
+	}
+	opts := []scanner.Option{
+		scanner.WithOptions(scanner.Options{
+			Only200:     sc.Only200,
+			SearchBing:  sc.SearchBing && p.cfg.BingEnabled,
+			Concurrency: p.cfg.DefaultConcurrency,
+			MaxPaths:    p.cfg.MaxPaths,
+
+			Adaptive:       p.cfg.AdaptiveConcurrency,
+			MinConcurrency: p.cfg.MinConcurrency,
+			MaxConcurrency: p.cfg.MaxConcurrency,
+
+			Fingerprint: p.cfg.FingerprintEnabled,
+			InspectBody: p.cfg.InspectBodies,
+		}),
+		scanner.WithHTTPClient(client),
+		scanner.WithRobotsCache(p.cache, p.cfg.RobotsCacheTTL),
+		scanner.OnProgress(func(done, total int) {
+			p.cache.SetProgress(runCtx, scanID, done, total)
+		}),
+		scanner.OnConcurrency(func(prof types.ConcurrencyProfile) {
+			sc.Concurrency = &prof
+		}),
+		scanner.OnRobots(func(status int) { sc.RobotsStatus = status }),
+	}
+	if len(p.cfg.HostHeaders) > 0 {
+		hook, _ := scanner.HostHeaders(p.cfg.HostHeaders) // validated by config.Load
+		opts = append(opts, scanner.OnRequest(hook))
+	}
+	if p.cfg.TraceRequests {
+		opts = append(opts, scanner.OnResponse(func(ex scanner.Exchange) {
+			if ex.Err != nil {
+				log.Printf("scan %s: %s %s %s: %v", scanID, ex.Kind, ex.Request.Method, ex.Request.URL, ex.Err)
+				return
+			}
+			log.Printf("scan %s: %s %s %s -> %d (%s)", scanID, ex.Kind, ex.Request.Method, ex.Request.URL, ex.Response.StatusCode, ex.Duration.Round(time.Millisecond))
+		}))
+	}
+	s := scanner.New(opts...)
 
 	start := time.Now()
 	results, disallow, err := s.Run(runCtx, sc.Target)
+	if recorder != nil {
+		// Kept for failed scans too: the recording shows why.
+		p.saveHAR(ctx, scanID, recorder)
+	}
 	if err != nil {
 		return p.fail(ctx, scanID, err.Error())
 	}
 	p.cache.SetProgress(ctx, scanID, len(results), len(disallow))
 
+	q := p.assess(ctx, sc, results)
+	sc.Degraded, sc.DegradedReason = q.Degraded, q.Reason
+	sc.Disallow = disallow
+
 	if err := p.persist(ctx, scanID, sc, results, time.Since(start)); err != nil {
 		return err
 	}
@@ -119,13 +165,54 @@ func (p *Processor) Handle(ctx context.Context, scanID string) (err error) {
 	// Populate the result cache so identical requests skip the queue.
 	_ = p.cache.PutScanID(ctx, sc.OptionsHash, scanID, p.cfg.ScanCacheTTL)
 
-	// For scheduled scans, diff against the previous run and alert on changes.
+	// For scheduled scans, diff against the previous run and alert on changes —
+	// unless this run was degraded, which would only produce false alerts.
 	if sc.ScheduleID != nil {
-		p.diffAndNotify(ctx, sc, results)
+		if sc.Degraded {
+			log.Printf("scan %s degraded, skipping diff: %s", scanID, sc.DegradedReason)
+		} else {
+			p.diffAndNotify(ctx, sc, results)
+		}
 	}
 	return nil
 }
 
+// saveHAR stores the scan's recording as a gzip'd artifact. Best-effort: a
+// failure here never fails the scan.
+func (p *Processor) saveHAR(ctx context.Context, scanID string, rec *har.Recorder) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := rec.Write(zw); err != nil {
+		log.Printf("scan %s: encoding HAR: %v", scanID, err)
+		return
+	}
+	zw.Close()
+	if err := p.store.PutArtifact(ctx, scanID, store.ArtifactHAR, buf.Bytes(), time.Now().Add(p.cfg.HARRetention)); err != nil {
+		log.Printf("scan %s: storing HAR: %v", scanID, err)
+		return
+	}
+	if n, err := p.store.DeleteExpiredArtifacts(ctx); err == nil && n > 0 {
+		log.Printf("pruned %d expired artifacts", n)
+	}
+}
+
+// assess flags scans a WAF or CDN interfered with. The baseline for a sudden
+// status shift is the last non-degraded scan, the one diffAndNotify compares
+// against: a block lasting several runs would otherwise become its own
+// baseline and pass from the second run on.
+func (p *Processor) assess(ctx context.Context, sc store.Scan, results []types.Result) quality.Assessment {
+	var prev []quality.Sample
+	if last, err := p.store.PreviousDoneScan(ctx, sc.OptionsHash, sc.ID); err == nil {
+		if rows, err := p.store.ListResults(ctx, last.ID); err == nil {
+			prev = make([]quality.Sample, len(rows))
+			for i, r := range rows {
+				prev[i] = quality.Sample{StatusCode: r.StatusCode, Class: r.Class, Err: r.Error != ""}
+			}
+		}
+	}
+	return quality.Assess(prev, quality.FromResults(results))
+}
+
 // diffAndNotify alerts on changes vs the previous run, per the schedule's
 // settings. Best-effort: failures here never fail the scan.
 func (p *Processor) diffAndNotify(ctx context.Context, sc store.Scan, results []types.Result) {
@@ -143,7 +230,15 @@ func (p *Processor) diffAndNotify(ctx context.Context, sc store.Scan, results []
 		return
 	}
 
-	d := diff.Compute(toProbes(prevRows), probesFromResults(results))
+	cur := diff.FromResults(results)
+	d := diff.Compute(toProbes(prevRows), cur)
+	if len(sch.AlertFingerprints) > 0 {
+		// A fingerprint filter only ever alerts on a match.
+		d = d.WithFingerprints(cur, sch.AlertFingerprints)
+		if !d.HasChanges() {
+			return
+		}
+	}
 	if sch.NotifyOnChange && !d.HasChanges() {
 		return
 	}
@@ -155,6 +250,7 @@ func (p *Processor) diffAndNotify(ctx context.Context, sc store.Scan, results []
 		ScheduleID:        sch.ID,
 		NewlyReachable:    d.NewlyReachable,
 		NoLongerReachable: d.NoLongerReachable,
+		Fingerprints:      fingerprintsFor(d.NewlyReachable, results),
 	}
 	if err := p.notifier.Send(ctx, sch.NotifyWebhook, alert); err != nil {
 		log.Printf("notify schedule %s: %v", sch.ID, err)
@@ -164,15 +260,22 @@ func (p *Processor) diffAndNotify(ctx context.Context, sc store.Scan, results []
 func toProbes(rows []store.ResultRow) []diff.Probe {
 	out := make([]diff.Probe, len(rows))
 	for i, r := range rows {
-		out[i] = diff.Probe{URL: r.URL, StatusCode: r.StatusCode}
+		out[i] = diff.Probe{URL: r.URL, StatusCode: r.StatusCode, Class: r.Class, Fingerprints: r.Fingerprints}
 	}
 	return out
 }
 
-func probesFromResults(results []types.Result) []diff.Probe {
-	out := make([]diff.Probe, 0, len(results))
+// fingerprintsFor maps each alerted URL to its technologies, if any.
+func fingerprintsFor(urls []string, results []types.Result) map[string][]string {
+	want := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		want[u] = true
+	}
+	out := map[string][]string{}
 	for _, r := range results {
-		out = append(out, diff.Probe{URL: r.URL, StatusCode: r.StatusCode})
+		if want[r.URL] && len(r.Fingerprints) > 0 {
+			out[r.URL] = r.Fingerprints
+		}
 	}
 	return out
 }
@@ -182,10 +285,13 @@ func (p *Processor) persist(ctx context.Context, scanID string, sc store.Scan, r
 	var status200, other, errs int
 	for _, r := range results {
 		row := store.ResultRow{
-			URL:        r.URL,
-			StatusCode: r.StatusCode,
-			Status:     r.Status,
-			Source:     r.Source,
+			URL:          r.URL,
+			StatusCode:   r.StatusCode,
+			Status:       r.Status,
+			Source:       r.Source,
+			Fingerprints: r.Fingerprints,
+			Class:        r.Class,
+			AuthScheme:   r.AuthScheme,
 		}
 		if r.Error != nil {
 			row.Error = r.Error.Error()
@@ -3,11 +3,18 @@ package logo
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/zvdy/parsero-go/pkg/colors"
 )
 
 func PrintLogo() {
+	Fprint(os.Stdout)
+}
+
+// Fprint writes the banner to w.
+func Fprint(w io.Writer) {
 	hello := `
       ____
      |  _ \ __ _ _ __ ___  ___ _ __ ___  
@@ -15,5 +22,5 @@ func PrintLogo() {
      |  __/ (_| | |  \__ \  __/ | | (_) |
      |_|   \__,_|_|  |___/\___|_|  \___/ 
     `
-	fmt.Println(colors.YELLOW + hello + colors.ENDC)
+	fmt.Fprintln(w, colors.YELLOW+hello+colors.ENDC)
 }
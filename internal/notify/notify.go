@@ -24,6 +24,8 @@ type Alert struct {
 	ScheduleID        string   `json:"schedule_id,omitempty"`
 	NewlyReachable    []string `json:"newly_reachable,omitempty"`
 	NoLongerReachable []string `json:"no_longer_reachable,omitempty"`
+	// Fingerprints maps newly reachable URLs to their detected technologies.
+	Fingerprints map[string][]string `json:"fingerprints,omitempty"`
 }
 
 // Notifier posts alerts to webhooks. Guard is the per-host SSRF check, injectable
@@ -101,6 +103,10 @@ func slackText(a Alert) string {
 	if len(a.NewlyReachable) > 0 {
 		fmt.Fprintf(&b, "*%d newly reachable* Disallow path(s):\n", len(a.NewlyReachable))
 		for _, u := range a.NewlyReachable {
+			if fps := a.Fingerprints[u]; len(fps) > 0 {
+				fmt.Fprintf(&b, "• %s (%s)\n", u, strings.Join(fps, ", "))
+				continue
+			}
 			fmt.Fprintf(&b, "• %s\n", u)
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -63,3 +64,14 @@ func TestSendErrorStatus(t *testing.T) {
 		t.Error("expected error on 5xx webhook response")
 	}
 }
+
+func TestSlackTextFingerprints(t *testing.T) {
+	txt := slackText(Alert{
+		Target:         "x",
+		NewlyReachable: []string{"http://x/ci"},
+		Fingerprints:   map[string][]string{"http://x/ci": {"jenkins"}},
+	})
+	if !strings.Contains(txt, "http://x/ci (jenkins)") {
+		t.Errorf("slack text missing fingerprint: %q", txt)
+	}
+}
@@ -0,0 +1,282 @@
+// Package policy decides whether a scan should fail a CI job. A policy is a
+// list of rules; each rule selects results by class, severity, source or
+// status and fires once at least Count of them match. The outcome maps to a
+// distinct process exit code so a pipeline can tell "found something" apart
+// from "couldn't scan".
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/sensitive"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// Exit codes, in the order Combine prefers them.
+const (
+	ExitOK        = 0
+	ExitFindings  = 1
+	ExitUsage     = 2 // bad flags; set by the CLI, never by Evaluate
+	ExitScanError = 3
+	ExitNoRobots  = 4
+)
+
+const (
+	OutcomePass      = "pass"
+	OutcomeFindings  = "findings"
+	OutcomeScanError = "scan_error"
+	OutcomeNoRobots  = "no_robots"
+)
+
+// Severities, lowest first; they match the SARIF levels.
+var severities = []string{"note", "warning", "error"}
+
+// shorthands expand to full rule specs.
+var shorthands = map[string]string{
+	"any":       "severity=note",
+	"reachable": "class=open",
+	"sensitive": "class=open severity=error",
+}
+
+var knownClasses = []string{
+	classify.Open, classify.Auth, classify.Login, classify.Forbidden,
+	classify.Blocked, classify.NotFound, classify.Other,
+}
+
+// Rule is one --fail-on spec, e.g. "class=open,login severity=warning count=3".
+type Rule struct {
+	Spec        string
+	Classes     []string
+	Sources     []string
+	Statuses    []string // "200" or a class like "2xx"
+	MinSeverity string
+	Count       int
+}
+
+type Policy []Rule
+
+// Parse builds a policy from --fail-on specs. Each spec is whitespace-separated
+// key=value terms (class, severity, source, status, count) and the shorthands
+// "any", "reachable" and "sensitive", e.g. "sensitive count=2".
+func Parse(specs []string) (Policy, error) {
+	var p Policy
+	for _, spec := range specs {
+		r, err := ParseRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, r)
+	}
+	return p, nil
+}
+
+func ParseRule(spec string) (Rule, error) {
+	spec = strings.TrimSpace(spec)
+	r := Rule{Spec: spec, Count: 1}
+	var terms []string
+	for _, term := range strings.Fields(spec) {
+		if full, ok := shorthands[strings.ToLower(term)]; ok {
+			terms = append(terms, strings.Fields(full)...)
+		} else {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return Rule{}, fmt.Errorf("empty --fail-on rule")
+	}
+	filtered := false
+	for _, term := range terms {
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("rule %q: expected key=value, got %q", r.Spec, term)
+		}
+		values := strings.Split(strings.ToLower(value), ",")
+		switch strings.ToLower(key) {
+		case "class":
+			for _, v := range values {
+				if !slices.Contains(knownClasses, v) {
+					return Rule{}, fmt.Errorf("rule %q: unknown class %q", r.Spec, v)
+				}
+			}
+			r.Classes, filtered = values, true
+		case "source":
+			r.Sources, filtered = values, true
+		case "status":
+			for _, v := range values {
+				if !validStatus(v) {
+					return Rule{}, fmt.Errorf("rule %q: invalid status %q", r.Spec, v)
+				}
+			}
+			r.Statuses, filtered = values, true
+		case "severity":
+			if !slices.Contains(severities, values[0]) || len(values) > 1 {
+				return Rule{}, fmt.Errorf("rule %q: severity must be note, warning or error", r.Spec)
+			}
+			r.MinSeverity, filtered = values[0], true
+		case "count":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("rule %q: count must be a positive integer", r.Spec)
+			}
+			r.Count = n
+		default:
+			return Rule{}, fmt.Errorf("rule %q: unknown key %q", r.Spec, key)
+		}
+	}
+	if !filtered {
+		return Rule{}, fmt.Errorf("rule %q: needs at least one of class, severity, source or status", r.Spec)
+	}
+	return r, nil
+}
+
+func validStatus(v string) bool {
+	if len(v) == 3 && v[0] >= '1' && v[0] <= '5' && v[1:] == "xx" {
+		return true
+	}
+	n, err := strconv.Atoi(v)
+	return err == nil && n >= 100 && n <= 599
+}
+
+// Severity rates a result the way the SARIF report does: a reachable path is
+// a warning, or an error when its name looks sensitive; a login page is a
+// note. Anything else has no severity. An analyzer finding raises the
+// rating to its own severity.
+func Severity(r types.Result) string {
+	if r.Error != nil {
+		return ""
+	}
+	sev := ""
+	switch classify.Effective(r.Class, r.StatusCode) {
+	case classify.Open:
+		sev = "warning"
+		if sensitive.Is(r.URL) {
+			sev = "error"
+		}
+	case classify.Login:
+		sev = "note"
+	}
+	for _, f := range r.Findings {
+		if slices.Index(severities, f.Severity) > slices.Index(severities, sev) {
+			sev = f.Severity
+		}
+	}
+	return sev
+}
+
+func (r Rule) matches(res types.Result) bool {
+	if res.Error != nil {
+		return false
+	}
+	if len(r.Classes) > 0 && !slices.Contains(r.Classes, classify.Effective(res.Class, res.StatusCode)) {
+		return false
+	}
+	if len(r.Sources) > 0 {
+		src := res.Source
+		if src == "" {
+			src = "robots"
+		}
+		if !slices.Contains(r.Sources, src) {
+			return false
+		}
+	}
+	if len(r.Statuses) > 0 && !slices.ContainsFunc(r.Statuses, func(s string) bool { return statusMatches(s, res.StatusCode) }) {
+		return false
+	}
+	if r.MinSeverity != "" && slices.Index(severities, Severity(res)) < slices.Index(severities, r.MinSeverity) {
+		return false
+	}
+	return true
+}
+
+func statusMatches(spec string, code int) bool {
+	if strings.HasSuffix(spec, "xx") {
+		return code/100 == int(spec[0]-'0')
+	}
+	return strconv.Itoa(code) == spec
+}
+
+// Input is one target's scan as the policy sees it.
+type Input struct {
+	Results  []types.Result
+	Err      error // fatal scan error, if any
+	NoRobots bool  // robots.txt answered 404
+	Degraded bool
+	Reason   string // degraded reason
+}
+
+// Evaluate decides one target. Findings win over everything, since a hit is
+// real even on a partly blocked scan; then a missing robots.txt; then any
+// other scan error or a degraded scan, whose clean result can't be trusted.
+func (p Policy) Evaluate(in Input) types.PolicyDecision {
+	d := types.PolicyDecision{Outcome: OutcomePass, ExitCode: ExitOK}
+	for _, r := range p {
+		out := types.RuleOutcome{Rule: r.Spec, Threshold: r.Count}
+		for _, res := range in.Results {
+			if r.matches(res) {
+				out.Matches++
+				out.URLs = append(out.URLs, res.URL)
+			}
+		}
+		out.Triggered = out.Matches >= r.Count
+		d.Rules = append(d.Rules, out)
+		if out.Triggered {
+			d.Outcome, d.ExitCode = OutcomeFindings, ExitFindings
+		}
+	}
+	switch {
+	case d.Outcome == OutcomeFindings:
+	case in.NoRobots:
+		d.Outcome, d.ExitCode = OutcomeNoRobots, ExitNoRobots
+		if in.Err != nil {
+			d.Reason = in.Err.Error()
+		}
+	case in.Err != nil:
+		d.Outcome, d.ExitCode, d.Reason = OutcomeScanError, ExitScanError, in.Err.Error()
+	case in.Degraded:
+		d.Outcome, d.ExitCode, d.Reason = OutcomeScanError, ExitScanError, "degraded: "+in.Reason
+	}
+	d.Failed = d.ExitCode != ExitOK
+	return d
+}
+
+// ScanExit is the exit code for a scan run without --fail-on rules: a missing
+// robots.txt or a scan error still fails it, so CI notices a scan that never
+// happened. A degraded scan passes; with no rules there is no clean result to
+// distrust.
+func ScanExit(in Input) int {
+	switch {
+	case in.NoRobots:
+		return ExitNoRobots
+	case in.Err != nil:
+		return ExitScanError
+	}
+	return ExitOK
+}
+
+// Combine picks the process exit code for several targets: findings first,
+// then scan errors, then missing robots.txt files.
+func Combine(decisions []types.PolicyDecision) int {
+	code := ExitOK
+	for _, d := range decisions {
+		if rank(d.ExitCode) > rank(code) {
+			code = d.ExitCode
+		}
+	}
+	return code
+}
+
+func rank(code int) int {
+	switch code {
+	case ExitFindings:
+		return 3
+	case ExitScanError:
+		return 2
+	case ExitNoRobots:
+		return 1
+	}
+	return 0
+}
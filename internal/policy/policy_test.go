@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+var results = []types.Result{
+	{URL: "http://x/admin", StatusCode: 200, Class: "open"},       // error
+	{URL: "http://x/docs", StatusCode: 200, Class: "open"},        // warning
+	{URL: "http://x/portal", StatusCode: 200, Class: "login"},     // note
+	{URL: "http://x/backup", StatusCode: 403, Class: "forbidden"}, // none
+	{URL: "http://x/tmp", StatusCode: 200, Source: "bing"},        // warning, unclassified
+	{URL: "http://x/slow", Error: errors.New("timeout")},
+}
+
+func TestParseRule(t *testing.T) {
+	for _, bad := range []string{"", "class", "class=nope", "severity=high", "count=0", "count=2", "status=999", "colour=red"} {
+		if _, err := ParseRule(bad); err == nil {
+			t.Errorf("ParseRule(%q) should fail", bad)
+		}
+	}
+	r, err := ParseRule("sensitive")
+	if err != nil || r.MinSeverity != "error" || len(r.Classes) != 1 {
+		t.Errorf("shorthand sensitive = %+v, %v", r, err)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	cases := map[string]int{
+		"sensitive":                    1,
+		"reachable":                    3,
+		"any":                          4,
+		"severity=warning":             3,
+		"class=login":                  1,
+		"class=open source=bing":       1,
+		"status=4xx":                   1,
+		"status=200,403 source=robots": 4,
+	}
+	for spec, want := range cases {
+		p, err := Parse([]string{spec})
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		if got := p.Evaluate(Input{Results: results}).Rules[0].Matches; got != want {
+			t.Errorf("%s matched %d, want %d", spec, got, want)
+		}
+	}
+}
+
+func TestEvaluateOutcomes(t *testing.T) {
+	p, err := Parse([]string{"reachable count=4", "sensitive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := p.Evaluate(Input{Results: results, Degraded: true, Reason: "waf"})
+	if d.Outcome != OutcomeFindings || d.ExitCode != ExitFindings || !d.Failed {
+		t.Errorf("findings should win over degraded: %+v", d)
+	}
+	if d.Rules[0].Triggered || !d.Rules[1].Triggered {
+		t.Errorf("unexpected rule triggers: %+v", d.Rules)
+	}
+
+	strict, _ := Parse([]string{"class=auth"})
+	checks := []struct {
+		in   Input
+		want string
+		code int
+	}{
+		{Input{Results: results}, OutcomePass, ExitOK},
+		{Input{Results: results, Degraded: true}, OutcomeScanError, ExitScanError},
+		{Input{Err: errors.New("boom")}, OutcomeScanError, ExitScanError},
+		{Input{Err: errors.New("no robots"), NoRobots: true}, OutcomeNoRobots, ExitNoRobots},
+	}
+	for _, c := range checks {
+		if d := strict.Evaluate(c.in); d.Outcome != c.want || d.ExitCode != c.code {
+			t.Errorf("Evaluate(%+v) = %s/%d, want %s/%d", c.in, d.Outcome, d.ExitCode, c.want, c.code)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	ds := []types.PolicyDecision{{ExitCode: ExitNoRobots}, {ExitCode: ExitOK}, {ExitCode: ExitScanError}}
+	if got := Combine(ds); got != ExitScanError {
+		t.Errorf("Combine = %d, want %d", got, ExitScanError)
+	}
+	ds = append(ds, types.PolicyDecision{ExitCode: ExitFindings})
+	if got := Combine(ds); got != ExitFindings {
+		t.Errorf("Combine = %d, want %d", got, ExitFindings)
+	}
+	if got := Combine(nil); got != ExitOK {
+		t.Errorf("Combine(nil) = %d", got)
+	}
+}
+
+func TestScanExit(t *testing.T) {
+	checks := []struct {
+		in   Input
+		code int
+	}{
+		{Input{}, ExitOK},
+		{Input{Degraded: true, Reason: "waf"}, ExitOK},
+		{Input{Err: errors.New("refused")}, ExitScanError},
+		{Input{NoRobots: true}, ExitNoRobots},
+		{Input{Err: errors.New("no robots"), NoRobots: true}, ExitNoRobots},
+	}
+	for _, c := range checks {
+		if got := ScanExit(c.in); got != c.code {
+			t.Errorf("ScanExit(%+v) = %d, want %d", c.in, got, c.code)
+		}
+	}
+}
+
+func TestSeverityFindings(t *testing.T) {
+	login := types.Result{URL: "http://x/login", StatusCode: 200, Class: "login"}
+	if got := Severity(login); got != "note" {
+		t.Fatalf("login: %q, want note", got)
+	}
+	login.Findings = []types.Finding{{Analyzer: "secrets", Severity: "error"}}
+	if got := Severity(login); got != "error" {
+		t.Errorf("login with an error finding: %q, want error", got)
+	}
+	blocked := types.Result{URL: "http://x/a", StatusCode: 403, Findings: []types.Finding{{Severity: "warning"}}}
+	if got := Severity(blocked); got != "warning" {
+		t.Errorf("403 with a warning finding: %q, want warning", got)
+	}
+}
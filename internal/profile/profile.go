@@ -0,0 +1,195 @@
+// Package profile loads named sets of CLI settings from a YAML config file, so
+// an engagement's flags live in one place and are picked with --profile.
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is a parsero config file.
+type File struct {
+	// Default is the profile used when --profile isn't given.
+	Default  string             `yaml:"default"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile holds scan settings. Field names follow the scan flags they fill;
+// zero values leave the flag alone.
+type Profile struct {
+	Concurrency       int               `yaml:"concurrency"`
+	TargetConcurrency int               `yaml:"target-concurrency"`
+	MaxRPS            float64           `yaml:"max-rps"`
+	Adaptive          bool              `yaml:"adaptive"`
+	Timeout           time.Duration     `yaml:"timeout"`
+	Headers           map[string]string `yaml:"headers"`
+	HostHeaders       []string          `yaml:"host-headers"`
+	Proxy             string            `yaml:"proxy"`
+	UserAgent         string            `yaml:"user-agent"`
+	Format            string            `yaml:"format"`
+	Output            string            `yaml:"output"`
+	Only200           bool              `yaml:"only200"`
+	SearchDisallow    bool              `yaml:"search-disallow"`
+	Archive           bool              `yaml:"archive"`
+	ArchiveURL        string            `yaml:"archive-url"`
+	ArchiveVersions   int               `yaml:"archive-versions"`
+	Fingerprint       bool              `yaml:"fingerprint"`
+	Inspect           bool              `yaml:"inspect"`
+	Tech              []string          `yaml:"tech"`
+	Analyze           []string          `yaml:"analyze"`
+	FailOn            []string          `yaml:"fail-on"`
+	Scope             Scope             `yaml:"scope"`
+}
+
+// Scope limits which targets a scan may touch.
+type Scope struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+}
+
+// Setting is one flag value a profile supplies. Repeatable flags yield one
+// Setting per value.
+type Setting struct {
+	Flag  string
+	Value string
+}
+
+// ErrNotFound reports a profile name the file doesn't define.
+var ErrNotFound = errors.New("profile not found")
+
+// Candidates lists where Find looks, in order: the working directory, then
+// $XDG_CONFIG_HOME/parsero (~/.config/parsero on Linux).
+func Candidates() []string {
+	paths := []string{".parsero.yaml", "parsero.yaml"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "parsero", "config.yaml"))
+	}
+	return paths
+}
+
+// Find returns the first config file in Candidates that exists, or "".
+func Find() string {
+	for _, p := range Candidates() {
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// Load reads and parses a config file.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a config file, rejecting keys it doesn't know so a typo
+// doesn't silently drop a setting.
+func Parse(data []byte) (File, error) {
+	var f File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return File{}, err
+	}
+	if f.Default != "" {
+		if _, ok := f.Profiles[f.Default]; !ok {
+			return File{}, fmt.Errorf("default profile %q: %w", f.Default, ErrNotFound)
+		}
+	}
+	return f, nil
+}
+
+// Profile returns the named profile, or the default one when name is empty.
+// With neither, it returns the zero Profile.
+func (f File) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = f.Default
+	}
+	if name == "" {
+		return Profile{}, nil
+	}
+	p, ok := f.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%q: %w", name, ErrNotFound)
+	}
+	return p, nil
+}
+
+// Settings flattens p into flag values, skipping unset fields.
+func (p Profile) Settings() []Setting {
+	var out []Setting
+	add := func(flag, value string) { out = append(out, Setting{flag, value}) }
+	if p.Concurrency > 0 {
+		add("concurrency", strconv.Itoa(p.Concurrency))
+	}
+	if p.TargetConcurrency > 0 {
+		add("target-concurrency", strconv.Itoa(p.TargetConcurrency))
+	}
+	if p.MaxRPS > 0 {
+		add("max-rps", strconv.FormatFloat(p.MaxRPS, 'f', -1, 64))
+	}
+	if p.ArchiveVersions > 0 {
+		add("archive-versions", strconv.Itoa(p.ArchiveVersions))
+	}
+	if p.Timeout > 0 {
+		add("timeout", p.Timeout.String())
+	}
+	for flag, v := range map[string]string{
+		"proxy":       p.Proxy,
+		"user-agent":  p.UserAgent,
+		"format":      p.Format,
+		"output":      p.Output,
+		"archive-url": p.ArchiveURL,
+	} {
+		if v != "" {
+			add(flag, v)
+		}
+	}
+	for flag, on := range map[string]bool{
+		"adaptive":        p.Adaptive,
+		"only200":         p.Only200,
+		"search-disallow": p.SearchDisallow,
+		"archive":         p.Archive,
+		"fingerprint":     p.Fingerprint,
+		"inspect":         p.Inspect,
+	} {
+		if on {
+			add(flag, "true")
+		}
+	}
+	names := make([]string, 0, len(p.Headers))
+	for name := range p.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add("header", name+": "+p.Headers[name])
+	}
+	for flag, values := range map[string][]string{
+		"tech":         p.Tech,
+		"analyze":      p.Analyze,
+		"host-header":  p.HostHeaders,
+		"fail-on":      p.FailOn,
+		"scope":        p.Scope.Include,
+		"out-of-scope": p.Scope.Exclude,
+	} {
+		for _, v := range values {
+			add(flag, v)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Flag < out[j].Flag })
+	return out
+}
@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sample = `
+default: acme
+profiles:
+  acme:
+    concurrency: 8
+    max-rps: 2.5
+    timeout: 10s
+    user-agent: acme-audit
+    proxy: http://127.0.0.1:8080
+    headers:
+      X-Engagement: ACME-42
+      Authorization: Bearer t
+    format: html
+    inspect: true
+    fail-on: [sensitive]
+    scope:
+      include: ["*.acme.test"]
+      exclude: [legacy.acme.test]
+  quick:
+    only200: true
+`
+
+func TestSettings(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := f.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Setting{
+		{"concurrency", "8"},
+		{"fail-on", "sensitive"},
+		{"format", "html"},
+		{"header", "Authorization: Bearer t"},
+		{"header", "X-Engagement: ACME-42"},
+		{"inspect", "true"},
+		{"max-rps", "2.5"},
+		{"out-of-scope", "legacy.acme.test"},
+		{"proxy", "http://127.0.0.1:8080"},
+		{"scope", "*.acme.test"},
+		{"timeout", "10s"},
+		{"user-agent", "acme-audit"},
+	}
+	if got := p.Settings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Settings() =\n%v\nwant\n%v", got, want)
+	}
+
+	q, _ := f.Profile("quick")
+	if got := q.Settings(); len(got) != 1 || got[0] != (Setting{"only200", "true"}) {
+		t.Errorf("quick = %v", got)
+	}
+	if _, err := f.Profile("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown profile: err = %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown key":     "profiles:\n  a:\n    concurency: 4\n",
+		"missing default": "default: b\nprofiles:\n  a: {}\n",
+		"bad duration":    "profiles:\n  a:\n    timeout: soon\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	if f, err := Parse(nil); err != nil || len(f.Profiles) != 0 {
+		t.Errorf("empty file: %+v, %v", f, err)
+	}
+}
+
+func TestFindXDG(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if d, _ := os.UserConfigDir(); d != dir {
+		t.Skip("user config dir doesn't follow XDG_CONFIG_HOME on this platform")
+	}
+	if got := Find(); got != "" {
+		t.Fatalf("Find() = %q with no config", got)
+	}
+	path := filepath.Join(dir, "parsero", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(sample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Find(); got != path {
+		t.Errorf("Find() = %q, want %q", got, path)
+	}
+}
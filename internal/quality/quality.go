@@ -0,0 +1,99 @@
+// Package quality decides whether a finished scan can be trusted. When a CDN
+// or WAF starts blocking the scanner every path turns into a 403 or a captcha
+// page, and diffing that against a healthy run would report everything as
+// "no longer reachable". A degraded scan is stored with its reason and kept
+// out of monitor diffs.
+package quality
+
+import (
+	"fmt"
+
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// Sample is one probe outcome; Err marks a transport error (no status).
+type Sample struct {
+	StatusCode int
+	Class      string
+	Err        bool
+}
+
+// FromResults converts scanner output into samples.
+func FromResults(results []types.Result) []Sample {
+	out := make([]Sample, len(results))
+	for i, r := range results {
+		out[i] = Sample{StatusCode: r.StatusCode, Class: r.Class, Err: r.Error != nil}
+	}
+	return out
+}
+
+type Assessment struct {
+	Degraded bool
+	Reason   string
+}
+
+const (
+	// blockedShare is the fraction of block pages that marks a scan degraded.
+	blockedShare = 0.5
+	// minUniform is the smallest scan where "every probe has the same status"
+	// is meaningful rather than a coincidence.
+	minUniform = 5
+)
+
+// Assess checks cur for block pages, blanket transport failures and a sudden
+// shift to one uniform status compared with prev (the last non-degraded scan
+// of the same target; nil when there is none).
+func Assess(prev, cur []Sample) Assessment {
+	if len(cur) == 0 {
+		return Assessment{}
+	}
+
+	var blocked, errs int
+	for _, s := range cur {
+		switch {
+		case s.Err:
+			errs++
+		case classify.Effective(s.Class, s.StatusCode) == classify.Blocked:
+			blocked++
+		}
+	}
+	if float64(blocked) >= blockedShare*float64(len(cur)) && blocked > 0 {
+		return Assessment{true, fmt.Sprintf("%d of %d probes hit WAF, bot-protection or rate-limit responses", blocked, len(cur))}
+	}
+	if errs == len(cur) && len(cur) >= minUniform {
+		return Assessment{true, fmt.Sprintf("all %d probes failed with transport errors", len(cur))}
+	}
+
+	if code, ok := uniformStatus(cur); ok && len(cur) >= minUniform {
+		if distinct := distinctStatuses(prev); distinct > 1 {
+			return Assessment{true, fmt.Sprintf("all %d probes returned %d; the previous scan saw %d distinct statuses", len(cur), code, distinct)}
+		}
+	}
+	return Assessment{}
+}
+
+func uniformStatus(samples []Sample) (int, bool) {
+	code := -1
+	for _, s := range samples {
+		if s.Err {
+			return 0, false
+		}
+		if code == -1 {
+			code = s.StatusCode
+		} else if s.StatusCode != code {
+			return 0, false
+		}
+	}
+	return code, code != -1
+}
+
+func distinctStatuses(samples []Sample) int {
+	seen := map[int]bool{}
+	for _, s := range samples {
+		if !s.Err {
+			seen[s.StatusCode] = true
+		}
+	}
+	return len(seen)
+}
@@ -0,0 +1,77 @@
+package quality
+
+import "testing"
+
+func samples(codes ...int) []Sample {
+	out := make([]Sample, len(codes))
+	for i, c := range codes {
+		out[i] = Sample{StatusCode: c}
+	}
+	return out
+}
+
+func TestAssessBlockPages(t *testing.T) {
+	cur := []Sample{
+		{StatusCode: 403, Class: "waf"},
+		{StatusCode: 403, Class: "waf"},
+		{StatusCode: 200, Class: "open"},
+	}
+	a := Assess(nil, cur)
+	if !a.Degraded || a.Reason == "" {
+		t.Errorf("expected degraded with reason, got %+v", a)
+	}
+}
+
+func TestAssessUniformShift(t *testing.T) {
+	prev := samples(200, 403, 404, 404, 200)
+	cur := samples(403, 403, 403, 403, 403)
+	if a := Assess(prev, cur); !a.Degraded {
+		t.Errorf("uniform 403 after a mixed scan should be degraded, got %+v", a)
+	}
+	// Uniform but consistent with the previous run is just how the site is.
+	if a := Assess(cur, cur); a.Degraded {
+		t.Errorf("unchanged uniform scan should not be degraded, got %+v", a)
+	}
+	// No history: nothing to shift from.
+	if a := Assess(nil, cur); a.Degraded {
+		t.Errorf("first scan should not be degraded by uniformity, got %+v", a)
+	}
+}
+
+// A block lasting several runs stays degraded: each run is compared with
+// the last non-degraded one, as the worker does, not with the blocked run
+// before it.
+func TestAssessBlockAcrossRuns(t *testing.T) {
+	runs := [][]Sample{
+		samples(200, 403, 404, 404, 200),
+		samples(403, 403, 403, 403, 403),
+		samples(403, 403, 403, 403, 403),
+	}
+	want := []bool{false, true, true}
+	var baseline []Sample
+	for i, cur := range runs {
+		a := Assess(baseline, cur)
+		if a.Degraded != want[i] {
+			t.Errorf("run %d: degraded = %t, want %t (%s)", i+1, a.Degraded, want[i], a.Reason)
+		}
+		if !a.Degraded {
+			baseline = cur
+		}
+	}
+}
+
+func TestAssessTransportErrors(t *testing.T) {
+	cur := make([]Sample, 6)
+	for i := range cur {
+		cur[i].Err = true
+	}
+	if a := Assess(nil, cur); !a.Degraded {
+		t.Errorf("all-error scan should be degraded, got %+v", a)
+	}
+}
+
+func TestAssessHealthy(t *testing.T) {
+	if a := Assess(samples(200, 404, 403), samples(200, 404, 404)); a.Degraded {
+		t.Errorf("healthy scan flagged degraded: %+v", a)
+	}
+}
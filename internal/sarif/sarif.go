@@ -1,19 +1,29 @@
 // Package sarif renders scan results as SARIF 2.1.0 so they can be uploaded to
 // GitHub code scanning or any SARIF-aware security dashboard. Each reachable
 // Disallow path becomes a result; obviously-sensitive paths are raised to
-// "error" level.
+// "error" level. Login forms guarding a Disallow path are reported as notes.
 package sarif
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"strings"
 
-	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/lint"
+	"github.com/zvdy/parsero-go/pkg/sensitive"
+	"github.com/zvdy/parsero-go/pkg/types"
 )
 
 const (
 	version = "2.1.0"
 	schema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
 	ruleID  = "exposed-disallow-path"
+	loginID = "disallow-path-login-page"
+
+	fingerprintKey = "parseroFinding/v1"
 )
 
 type Report struct {
@@ -23,8 +33,13 @@ type Report struct {
 }
 
 type run struct {
-	Tool    tool     `json:"tool"`
-	Results []result `json:"results"`
+	Tool              tool               `json:"tool"`
+	AutomationDetails *automationDetails `json:"automationDetails,omitempty"`
+	Results           []result           `json:"results"`
+}
+
+type automationDetails struct {
+	ID string `json:"id"`
 }
 
 type tool struct {
@@ -44,10 +59,11 @@ type rule struct {
 }
 
 type result struct {
-	RuleID    string     `json:"ruleId"`
-	Level     string     `json:"level"`
-	Message   textBlock  `json:"message"`
-	Locations []location `json:"locations"`
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             textBlock         `json:"message"`
+	Locations           []location        `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
 }
 
 type textBlock struct {
@@ -60,38 +76,125 @@ type location struct {
 
 type physicalLocation struct {
 	ArtifactLocation artifactLocation `json:"artifactLocation"`
+	Region           *region          `json:"region,omitempty"`
+}
+
+type region struct {
+	StartLine int `json:"startLine"`
 }
 
 type artifactLocation struct {
 	URI string `json:"uri"`
 }
 
-var sensitiveMarkers = []string{
-	"admin", "login", "wp-admin", "phpmyadmin", ".git", ".env", "backup",
-	"config", "secret", "private", "password", "db", "sql", "dump", ".ssh",
-	"credential", "token", "api-key", "apikey", "internal",
+// Target is one scanned host and its probe results — the storage-neutral
+// input to Build, filled from the CLI's results or parserod's stored rows.
+type Target struct {
+	Name    string
+	Results []types.Result
 }
 
-// Build reports only the reachable (HTTP 200) Disallow paths — what's actually
-// accessible is what matters.
-func Build(scan store.Scan, rows []store.ResultRow) Report {
-	var results []result
-	for _, r := range rows {
-		if r.StatusCode != 200 {
+// Build reports the reachable ("open") Disallow paths — what's actually
+// accessible is what matters — plus login pages as notes. Auth walls, 403s and
+// WAF pages are left out. Each target gets its own run, categorised by target
+// so code scanning tracks them separately.
+func Build(targets ...Target) Report {
+	runs := make([]run, 0, len(targets))
+	for _, t := range targets {
+		runs = append(runs, run{
+			Tool:              tool{Driver: scanDriver()},
+			AutomationDetails: &automationDetails{ID: "parsero/" + t.Name + "/"},
+			Results:           scanResults(t),
+		})
+	}
+	return Report{Schema: schema, Version: version, Runs: runs}
+}
+
+func scanResults(t Target) []result {
+	results := []result{}
+	for _, r := range t.Results {
+		if r.Error != nil {
+			continue
+		}
+		var res result
+		switch classify.Effective(r.Class, r.StatusCode) {
+		case classify.Open:
+			res = result{
+				RuleID:  ruleID,
+				Level:   level(r.URL),
+				Message: textBlock{Text: "Disallow path is reachable: " + r.URL},
+			}
+		case classify.Login:
+			res = result{
+				RuleID:  loginID,
+				Level:   "note",
+				Message: textBlock{Text: "Disallow path serves a login page: " + r.URL},
+			}
+		default:
 			continue
 		}
+		res.Locations = []location{{
+			PhysicalLocation: physicalLocation{
+				ArtifactLocation: artifactLocation{URI: r.URL},
+			},
+		}}
+		// URLs have no line to hash, so alerts are matched across runs on
+		// rule + target + URL instead.
+		res.PartialFingerprints = map[string]string{fingerprintKey: fingerprint(res.RuleID, t.Name, r.URL)}
+		results = append(results, res)
+	}
+	return results
+}
+
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+func scanDriver() driver {
+	return driver{
+		Name:           "parsero",
+		InformationURI: "https://github.com/zvdy/parsero-go",
+		Rules: []rule{{
+			ID:               ruleID,
+			Name:             "ExposedDisallowPath",
+			ShortDescription: textBlock{Text: "A robots.txt Disallow path is publicly reachable."},
+		}, {
+			ID:               loginID,
+			Name:             "DisallowPathLoginPage",
+			ShortDescription: textBlock{Text: "A robots.txt Disallow path serves a login form."},
+		}},
+	}
+}
+
+// Write encodes rep as indented JSON.
+func Write(w io.Writer, rep Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// BuildLint reports robots.txt lint findings against uri (the robots.txt URL
+// or file path), one result per finding with its line as the region.
+func BuildLint(uri string, findings []lint.Finding) Report {
+	rules := make([]rule, 0, len(lint.Rules))
+	for _, r := range lint.Rules {
+		rules = append(rules, rule{ID: r.ID, Name: r.Name, ShortDescription: textBlock{Text: r.Description}})
+	}
+	results := make([]result, 0, len(findings))
+	for _, f := range findings {
 		results = append(results, result{
-			RuleID:  ruleID,
-			Level:   level(r.URL),
-			Message: textBlock{Text: "Disallow path is reachable: " + r.URL},
+			RuleID:  f.RuleID,
+			Level:   f.Severity,
+			Message: textBlock{Text: f.Message},
 			Locations: []location{{
 				PhysicalLocation: physicalLocation{
-					ArtifactLocation: artifactLocation{URI: r.URL},
+					ArtifactLocation: artifactLocation{URI: uri},
+					Region:           &region{StartLine: f.Line},
 				},
 			}},
 		})
 	}
-
 	return Report{
 		Schema:  schema,
 		Version: version,
@@ -99,11 +202,7 @@ func Build(scan store.Scan, rows []store.ResultRow) Report {
 			Tool: tool{Driver: driver{
 				Name:           "parsero",
 				InformationURI: "https://github.com/zvdy/parsero-go",
-				Rules: []rule{{
-					ID:               ruleID,
-					Name:             "ExposedDisallowPath",
-					ShortDescription: textBlock{Text: "A robots.txt Disallow path is publicly reachable."},
-				}},
+				Rules:          rules,
 			}},
 			Results: results,
 		}},
@@ -111,11 +210,8 @@ func Build(scan store.Scan, rows []store.ResultRow) Report {
 }
 
 func level(url string) string {
-	lower := strings.ToLower(url)
-	for _, m := range sensitiveMarkers {
-		if strings.Contains(lower, m) {
-			return "error"
-		}
+	if sensitive.Is(url) {
+		return "error"
 	}
 	return "warning"
 }
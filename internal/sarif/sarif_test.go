@@ -4,16 +4,17 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/lint"
+	"github.com/zvdy/parsero-go/pkg/types"
 )
 
 func TestBuildOnlyIncludesReachable(t *testing.T) {
-	rows := []store.ResultRow{
+	rows := []types.Result{
 		{URL: "http://x/admin", StatusCode: 200},
 		{URL: "http://x/private", StatusCode: 403},
 		{URL: "http://x/open", StatusCode: 200},
 	}
-	rep := Build(store.Scan{Target: "x"}, rows)
+	rep := Build(Target{Name: "x", Results: rows})
 	if len(rep.Runs) != 1 {
 		t.Fatalf("expected 1 run, got %d", len(rep.Runs))
 	}
@@ -23,12 +24,31 @@ func TestBuildOnlyIncludesReachable(t *testing.T) {
 	}
 }
 
+func TestBuildUsesClassification(t *testing.T) {
+	rows := []types.Result{
+		{URL: "http://x/admin", StatusCode: 200, Class: "login"},
+		{URL: "http://x/blocked", StatusCode: 200, Class: "waf"},
+		{URL: "http://x/docs", StatusCode: 200, Class: "open"},
+	}
+	rep := Build(Target{Name: "x", Results: rows})
+	rules := map[string]string{}
+	for _, r := range rep.Runs[0].Results {
+		rules[r.Locations[0].PhysicalLocation.ArtifactLocation.URI] = r.RuleID
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected login + open results only, got %v", rules)
+	}
+	if rules["http://x/admin"] != loginID || rules["http://x/docs"] != ruleID {
+		t.Errorf("unexpected rule mapping: %v", rules)
+	}
+}
+
 func TestBuildSeverity(t *testing.T) {
-	rows := []store.ResultRow{
+	rows := []types.Result{
 		{URL: "http://x/admin", StatusCode: 200}, // sensitive -> error
 		{URL: "http://x/stuff", StatusCode: 200}, // normal -> warning
 	}
-	rep := Build(store.Scan{Target: "x"}, rows)
+	rep := Build(Target{Name: "x", Results: rows})
 	levels := map[string]string{}
 	for _, r := range rep.Runs[0].Results {
 		levels[r.Locations[0].PhysicalLocation.ArtifactLocation.URI] = r.Level
@@ -42,7 +62,7 @@ func TestBuildSeverity(t *testing.T) {
 }
 
 func TestBuildMarshals(t *testing.T) {
-	rep := Build(store.Scan{Target: "x"}, []store.ResultRow{{URL: "http://x/a", StatusCode: 200}})
+	rep := Build(Target{Name: "x", Results: []types.Result{{URL: "http://x/a", StatusCode: 200}}})
 	b, err := json.Marshal(rep)
 	if err != nil {
 		t.Fatalf("marshal: %v", err)
@@ -58,3 +78,38 @@ func TestBuildMarshals(t *testing.T) {
 		t.Error("missing $schema")
 	}
 }
+
+func TestBuildLintRegions(t *testing.T) {
+	findings := []lint.Finding{{RuleID: "syntax-error", Severity: "error", Line: 3, Message: "bad"}}
+	rep := BuildLint("http://x/robots.txt", findings)
+	res := rep.Runs[0].Results
+	if len(res) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(res))
+	}
+	loc := res[0].Locations[0].PhysicalLocation
+	if loc.Region == nil || loc.Region.StartLine != 3 {
+		t.Errorf("expected region startLine 3, got %+v", loc.Region)
+	}
+	if len(rep.Runs[0].Tool.Driver.Rules) != len(lint.Rules) {
+		t.Errorf("expected every lint rule in the driver")
+	}
+}
+
+func TestBuildRunPerTarget(t *testing.T) {
+	a := Target{Name: "a.example", Results: []types.Result{{URL: "http://a.example/admin", StatusCode: 200}}}
+	b := Target{Name: "b.example", Results: []types.Result{{URL: "http://b.example/admin", StatusCode: 200}}}
+	rep := Build(a, b)
+	if len(rep.Runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(rep.Runs))
+	}
+	if rep.Runs[0].AutomationDetails.ID == rep.Runs[1].AutomationDetails.ID {
+		t.Error("runs share an automation category")
+	}
+	fpA := rep.Runs[0].Results[0].PartialFingerprints[fingerprintKey]
+	if fpA == "" || fpA == rep.Runs[1].Results[0].PartialFingerprints[fingerprintKey] {
+		t.Errorf("expected distinct per-target fingerprints, got %q", fpA)
+	}
+	if again := Build(a).Runs[0].Results[0].PartialFingerprints[fingerprintKey]; again != fpA {
+		t.Error("fingerprint isn't stable across builds")
+	}
+}
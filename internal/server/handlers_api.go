@@ -1,21 +1,32 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/zvdy/parsero-go/internal/fingerprint"
 	"github.com/zvdy/parsero-go/internal/safety"
 	"github.com/zvdy/parsero-go/internal/sarif"
 	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/export"
+	"github.com/zvdy/parsero-go/pkg/types"
 )
 
 type createScanRequest struct {
 	Target     string `json:"target"`
 	Only200    bool   `json:"only200"`
 	SearchBing bool   `json:"search_bing"`
+	// HAR records the scan's traffic as a downloadable artifact. A recorded
+	// scan always runs fresh rather than reusing a cached one.
+	HAR bool `json:"har"`
 }
 
 type scanResponse struct {
@@ -25,6 +36,7 @@ type scanResponse struct {
 	Cached          bool    `json:"cached,omitempty"`
 	Only200         bool    `json:"only200"`
 	SearchBing      bool    `json:"search_bing"`
+	HAR             bool    `json:"har,omitempty"`
 	DurationSeconds float64 `json:"duration_seconds"`
 	TotalPaths      int     `json:"total_paths"`
 	Status200       int     `json:"status_200"`
@@ -32,6 +44,11 @@ type scanResponse struct {
 	Errors          int     `json:"errors"`
 	ErrorMessage    string  `json:"error_message,omitempty"`
 	CreatedAt       string  `json:"created_at"`
+	Degraded        bool    `json:"degraded,omitempty"`
+	DegradedReason  string  `json:"degraded_reason,omitempty"`
+	RobotsStatus    int     `json:"robots_status,omitempty"`
+
+	Concurrency *types.ConcurrencyProfile `json:"concurrency,omitempty"`
 }
 
 func toScanResponse(sc store.Scan, cached bool) scanResponse {
@@ -42,6 +59,7 @@ func toScanResponse(sc store.Scan, cached bool) scanResponse {
 		Cached:          cached,
 		Only200:         sc.Only200,
 		SearchBing:      sc.SearchBing,
+		HAR:             sc.RecordHAR,
 		DurationSeconds: sc.DurationSeconds,
 		TotalPaths:      sc.TotalPaths,
 		Status200:       sc.Status200,
@@ -49,6 +67,10 @@ func toScanResponse(sc store.Scan, cached bool) scanResponse {
 		Errors:          sc.Errors,
 		ErrorMessage:    sc.ErrorMessage,
 		CreatedAt:       sc.CreatedAt.Format(time.RFC3339),
+		Degraded:        sc.Degraded,
+		DegradedReason:  sc.DegradedReason,
+		RobotsStatus:    sc.RobotsStatus,
+		Concurrency:     sc.Concurrency,
 	}
 }
 
@@ -76,15 +98,17 @@ func (s *Server) submitScan(ctx context.Context, userID string, req createScanRe
 	hash := store.OptionsHash(target, req.Only200, req.SearchBing)
 
 	// Cache: Redis first, Postgres fallback.
-	if id, ok, _ := s.cache.GetScanID(ctx, hash); ok {
-		if sc, err := s.store.GetScan(ctx, id); err == nil && sc.Status == "done" {
+	if !req.HAR {
+		if id, ok, _ := s.cache.GetScanID(ctx, hash); ok {
+			if sc, err := s.store.GetScan(ctx, id); err == nil && sc.Status == "done" {
+				return sc, true, http.StatusOK, ""
+			}
+		}
+		if sc, err := s.store.FindCachedScan(ctx, hash, s.cfg.ScanCacheTTL); err == nil {
+			_ = s.cache.PutScanID(ctx, hash, sc.ID, s.cfg.ScanCacheTTL)
 			return sc, true, http.StatusOK, ""
 		}
 	}
-	if sc, err := s.store.FindCachedScan(ctx, hash, s.cfg.ScanCacheTTL); err == nil {
-		_ = s.cache.PutScanID(ctx, hash, sc.ID, s.cfg.ScanCacheTTL)
-		return sc, true, http.StatusOK, ""
-	}
 
 	// Backpressure: reject when the queue is saturated.
 	if s.cfg.MaxQueueDepth > 0 {
@@ -105,7 +129,7 @@ func (s *Server) submitScan(ctx context.Context, userID string, req createScanRe
 	// Persist then enqueue. If enqueue fails, release the slot we reserved.
 	id, err := s.store.CreateScan(ctx, store.Scan{
 		UserID: userID, Target: target, OptionsHash: hash,
-		Only200: req.Only200, SearchBing: req.SearchBing,
+		Only200: req.Only200, SearchBing: req.SearchBing, RecordHAR: req.HAR,
 	})
 	if err != nil {
 		s.cache.Release(ctx, userID)
@@ -138,8 +162,30 @@ func (s *Server) handleCreateScan(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, code, toScanResponse(sc, cached))
 }
 
+// maxPageSize caps ?limit on list endpoints.
+const maxPageSize = 200
+
+// handleListScans returns the caller's scans newest first, a page at a time:
+// ?limit=N (default 50, at most 200) and ?before=ID, the last scan of the
+// previous page.
 func (s *Server) handleListScans(w http.ResponseWriter, r *http.Request) {
-	scans, err := s.store.ListScansByUser(r.Context(), identity(r), 50)
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			writeErr(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxPageSize))
+			return
+		}
+		limit = n
+	}
+	before := r.URL.Query().Get("before")
+	if before != "" {
+		if _, err := s.loadOwnedScanID(r, before); err != nil {
+			s.writeScanLoadErr(w, err)
+			return
+		}
+	}
+	scans, err := s.store.ListScansByUser(r.Context(), identity(r), limit, before)
 	if err != nil {
 		writeErr(w, http.StatusInternalServerError, "could not list scans")
 		return
@@ -161,13 +207,18 @@ func (s *Server) handleGetScan(w http.ResponseWriter, r *http.Request) {
 }
 
 type resultResponse struct {
-	URL        string `json:"url"`
-	StatusCode int    `json:"status_code,omitempty"`
-	Status     string `json:"status,omitempty"`
-	Error      string `json:"error,omitempty"`
-	Source     string `json:"source"`
+	URL          string   `json:"url"`
+	StatusCode   int      `json:"status_code,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Source       string   `json:"source"`
+	Fingerprints []string `json:"fingerprints,omitempty"`
+	Class        string   `json:"class,omitempty"`
+	AuthScheme   string   `json:"auth_scheme,omitempty"`
 }
 
+// handleGetResults supports ?fingerprint=jenkins,grafana to keep only paths
+// matching any of the listed technologies.
 func (s *Server) handleGetResults(w http.ResponseWriter, r *http.Request) {
 	sc, err := s.loadOwnedScan(r)
 	if err != nil {
@@ -179,16 +230,32 @@ func (s *Server) handleGetResults(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusInternalServerError, "could not load results")
 		return
 	}
+	want := splitList(r.URL.Query().Get("fingerprint"))
 	out := make([]resultResponse, 0, len(rows))
 	for _, rw := range rows {
+		if len(want) > 0 && !fingerprint.Matches(rw.Fingerprints, want) {
+			continue
+		}
 		out = append(out, resultResponse{
 			URL: rw.URL, StatusCode: rw.StatusCode, Status: rw.Status,
-			Error: rw.Error, Source: rw.Source,
+			Error: rw.Error, Source: rw.Source, Fingerprints: rw.Fingerprints,
+			Class: rw.Class, AuthScheme: rw.AuthScheme,
 		})
 	}
 	writeJSON(w, http.StatusOK, out)
 }
 
+// splitList parses a comma-separated query or form value, dropping blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 func (s *Server) handleGetSARIF(w http.ResponseWriter, r *http.Request) {
 	sc, err := s.loadOwnedScan(r)
 	if err != nil {
@@ -203,12 +270,149 @@ func (s *Server) handleGetSARIF(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/sarif+json")
 	w.Header().Set("Content-Disposition", `attachment; filename="parsero-`+sc.ID+`.sarif"`)
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(sarif.Build(sc, rows))
+	json.NewEncoder(w).Encode(sarif.Build(sarif.Target{Name: sc.Target, Results: toResults(rows)}))
+}
+
+// handleGetReport renders the scan in any pkg/export format (json, csv,
+// markdown, html, junit) as a download.
+func (s *Server) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = "json"
+	}
+	format, ok := export.Lookup(name)
+	if !ok {
+		writeErr(w, http.StatusBadRequest, "format must be one of "+strings.Join(export.Formats(), ", "))
+		return
+	}
+	sc, err := s.loadOwnedScan(r)
+	if err != nil {
+		s.writeScanLoadErr(w, err)
+		return
+	}
+	rows, err := s.store.ListResults(r.Context(), sc.ID)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, "could not load results")
+		return
+	}
+	// Rendered up front so a failure can still be reported as a 500.
+	var buf bytes.Buffer
+	if err := format.Write(&buf, toExport(sc, rows)); err != nil {
+		writeErr(w, http.StatusInternalServerError, "could not render report")
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="parsero-`+sc.ID+format.Extension+`"`)
+	w.WriteHeader(http.StatusOK)
+	buf.WriteTo(w)
+}
+
+// handleGetHAR downloads a recorded scan's HAR file. It is stored gzip'd, and
+// sent that way to clients that accept it.
+func (s *Server) handleGetHAR(w http.ResponseWriter, r *http.Request) {
+	sc, err := s.loadOwnedScan(r)
+	if err != nil {
+		s.writeScanLoadErr(w, err)
+		return
+	}
+	if !sc.RecordHAR {
+		writeErr(w, http.StatusNotFound, `scan was not recorded; create it with "har": true`)
+		return
+	}
+	if sc.Status != "done" && sc.Status != "failed" {
+		writeErr(w, http.StatusConflict, "scan has not finished")
+		return
+	}
+	data, err := s.store.GetArtifact(r.Context(), sc.ID, store.ArtifactHAR)
+	if errors.Is(err, store.ErrNotFound) {
+		writeErr(w, http.StatusGone, "HAR recording has expired")
+		return
+	} else if err != nil {
+		writeErr(w, http.StatusInternalServerError, "could not load HAR")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="parsero-`+sc.ID+`.har"`)
+	w.Header().Set("Vary", "Accept-Encoding")
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(data)
+		return
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, "could not load HAR")
+		return
+	}
+	io.Copy(w, zr)
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip: listed,
+// or covered by "*", with a non-zero q-value.
+func acceptsGzip(header string) bool {
+	star := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if name, v, ok := strings.Cut(strings.TrimSpace(p), "="); ok && strings.EqualFold(name, "q") {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					q = f
+				} else {
+					q = 0
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip":
+			return q > 0
+		case "*":
+			star = q > 0
+		}
+	}
+	return star
+}
+
+// toResults converts stored rows back to the scanner's result type.
+func toResults(rows []store.ResultRow) []types.Result {
+	results := make([]types.Result, 0, len(rows))
+	for _, rw := range rows {
+		res := types.Result{
+			URL: rw.URL, StatusCode: rw.StatusCode, Status: rw.Status,
+			Source: rw.Source, Fingerprints: rw.Fingerprints,
+			Class: rw.Class, AuthScheme: rw.AuthScheme,
+		}
+		if rw.Error != "" {
+			res.Error = errors.New(rw.Error)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// toExport rebuilds the CLI's export shape from a stored scan, filtered and
+// counted the way the CLI writes an only200 scan.
+func toExport(sc store.Scan, rows []store.ResultRow) export.ScanResult {
+	ts := sc.CreatedAt
+	if sc.StartedAt != nil {
+		ts = *sc.StartedAt
+	}
+	sr := export.CreateScanResult(sc.Target, 0, toResults(rows), sc.Only200)
+	sr.Timestamp = ts.Format(time.RFC3339)
+	sr.Duration = sc.DurationSeconds
+	sr.Disallow = sc.Disallow
+	sr.Degraded, sr.DegradedReason = sc.Degraded, sc.DegradedReason
+	sr.Concurrency = sc.Concurrency
+	return sr
 }
 
 // loadOwnedScan rejects cross-tenant reads by checking ownership.
 func (s *Server) loadOwnedScan(r *http.Request) (store.Scan, error) {
-	sc, err := s.store.GetScan(r.Context(), r.PathValue("id"))
+	return s.loadOwnedScanID(r, r.PathValue("id"))
+}
+
+func (s *Server) loadOwnedScanID(r *http.Request, id string) (store.Scan, error) {
+	sc, err := s.store.GetScan(r.Context(), id)
 	if err != nil {
 		return store.Scan{}, err
 	}
@@ -0,0 +1,27 @@
+package server
+
+import "testing"
+
+func TestAcceptsGzip(t *testing.T) {
+	cases := map[string]bool{
+		"":                      false,
+		"gzip":                  true,
+		"gzip, deflate, br":     true,
+		"deflate, GZIP;q=0.5":   true,
+		"gzip;q=0":              false,
+		"gzip; q=0.0, identity": false,
+		"br;q=1, gzip;q=0.001":  true,
+		"*":                     true,
+		"*;q=0":                 false,
+		"gzip;q=0, *":           false,
+		"identity, *;q=0.5":     true,
+		"x-gzip":                true,
+		"gzip;q=nonsense":       false,
+		"deflate":               false,
+	}
+	for header, want := range cases {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("acceptsGzip(%q) = %t, want %t", header, got, want)
+		}
+	}
+}
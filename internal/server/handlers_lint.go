@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zvdy/parsero-go/internal/safety"
+	"github.com/zvdy/parsero-go/internal/sarif"
+	"github.com/zvdy/parsero-go/pkg/lint"
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+type lintResponse struct {
+	Target   string         `json:"target"`
+	Findings []lint.Finding `json:"findings"`
+}
+
+// handleLint fetches the target's robots.txt synchronously — one small GET —
+// and returns its lint findings as JSON, or SARIF with ?format=sarif.
+func (s *Server) handleLint(w http.ResponseWriter, r *http.Request) {
+	target, err := safety.NormalizeTarget(r.URL.Query().Get("target"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := safety.ResolveAndCheck(r.Context(), target); err != nil {
+		writeErr(w, http.StatusBadRequest, "target not allowed: "+err.Error())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "sarif" {
+		writeErr(w, http.StatusBadRequest, "format must be json or sarif")
+		return
+	}
+
+	sc := scanner.New(scanner.WithHTTPClient(safety.GuardedClient(s.cfg.ScanTimeout)))
+	data, err := sc.FetchRobots(r.Context(), target)
+	if errors.Is(err, scanner.ErrNoRobots) {
+		writeErr(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		writeErr(w, http.StatusBadGateway, "could not fetch robots.txt")
+		return
+	}
+
+	findings := lint.Lint(data)
+	if format == "sarif" {
+		w.Header().Set("Content-Type", "application/sarif+json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sarif.BuildLint(scanner.BaseURL(target)+"/robots.txt", findings))
+		return
+	}
+	if findings == nil {
+		findings = []lint.Finding{}
+	}
+	writeJSON(w, http.StatusOK, lintResponse{Target: target, Findings: findings})
+}
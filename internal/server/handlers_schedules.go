@@ -20,6 +20,8 @@ type createScheduleRequest struct {
 	SearchBing     bool   `json:"search_bing"`
 	NotifyWebhook  string `json:"notify_webhook"`
 	NotifyOnChange bool   `json:"notify_on_change"`
+	// AlertFingerprints restricts alerts to paths fingerprinted as one of these.
+	AlertFingerprints []string `json:"alert_fingerprints,omitempty"`
 }
 
 type scheduleResponse struct {
@@ -33,6 +35,8 @@ type scheduleResponse struct {
 	NotifyOnChange bool   `json:"notify_on_change"`
 	CreatedAt      string `json:"created_at"`
 	LastRunAt      string `json:"last_run_at,omitempty"`
+
+	AlertFingerprints []string `json:"alert_fingerprints,omitempty"`
 }
 
 func toScheduleResponse(sc store.Schedule) scheduleResponse {
@@ -40,7 +44,8 @@ func toScheduleResponse(sc store.Schedule) scheduleResponse {
 		ID: sc.ID, Target: sc.Target, Cron: sc.Cron, Enabled: sc.Enabled,
 		Only200: sc.Only200, SearchBing: sc.SearchBing,
 		NotifyWebhook: sc.NotifyWebhook, NotifyOnChange: sc.NotifyOnChange,
-		CreatedAt: sc.CreatedAt.Format(time.RFC3339),
+		CreatedAt:         sc.CreatedAt.Format(time.RFC3339),
+		AlertFingerprints: sc.AlertFingerprints,
 	}
 	if sc.LastRunAt != nil {
 		r.LastRunAt = sc.LastRunAt.Format(time.RFC3339)
@@ -76,6 +81,7 @@ func (s *Server) buildSchedule(ctx context.Context, userID string, req createSch
 		Only200:     req.Only200, SearchBing: req.SearchBing,
 		Cron: req.Cron, Enabled: true,
 		NotifyWebhook: req.NotifyWebhook, NotifyOnChange: req.NotifyOnChange,
+		AlertFingerprints: req.AlertFingerprints,
 	}, http.StatusOK, ""
 }
 
@@ -158,6 +164,8 @@ func (s *Server) handleUIScheduleSubmit(w http.ResponseWriter, r *http.Request)
 		SearchBing:     r.FormValue("search_bing") == "on",
 		NotifyWebhook:  r.FormValue("notify_webhook"),
 		NotifyOnChange: r.FormValue("notify_on_change") == "on",
+
+		AlertFingerprints: splitList(r.FormValue("alert_fingerprints")),
 	}
 	sch, _, msg := s.buildSchedule(r.Context(), identity(r), req)
 	if msg != "" {
@@ -4,19 +4,23 @@ import (
 	"net/http"
 
 	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/classify"
 )
 
 type uiResult struct {
-	URL    string
-	Code   int
-	Status string
-	Error  string
-	Source string
-	OK     bool
+	URL          string
+	Code         int
+	Status       string
+	Error        string
+	Source       string
+	Fingerprints []string
+	Class        string
+	AuthScheme   string
+	OK           bool
 }
 
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
-	scans, _ := s.store.ListScansByUser(r.Context(), identity(r), 20)
+	scans, _ := s.store.ListScansByUser(r.Context(), identity(r), 20, "")
 	schedules, _ := s.store.ListSchedulesByUser(r.Context(), identity(r))
 	s.render(w, "index", map[string]any{
 		"Identity":    identity(r),
@@ -85,7 +89,9 @@ func (s *Server) handleUIResults(w http.ResponseWriter, r *http.Request) {
 	for _, rw := range rows {
 		results = append(results, uiResult{
 			URL: rw.URL, Code: rw.StatusCode, Status: rw.Status,
-			Error: rw.Error, Source: rw.Source, OK: rw.StatusCode == 200,
+			Error: rw.Error, Source: rw.Source, Fingerprints: rw.Fingerprints,
+			Class: classify.Effective(rw.Class, rw.StatusCode), AuthScheme: rw.AuthScheme,
+			OK: classify.Reachable(rw.Class, rw.StatusCode),
 		})
 	}
 	s.render(w, "results_table", map[string]any{
@@ -4,9 +4,11 @@
 package server
 
 import (
+	"context"
 	"embed"
 	"html/template"
 	"net/http"
+	"time"
 
 	"github.com/zvdy/parsero-go/internal/cache"
 	"github.com/zvdy/parsero-go/internal/config"
@@ -20,16 +22,57 @@ var templatesFS embed.FS
 //go:embed static/*
 var staticFS embed.FS
 
+// Store is the part of *store.Store the handlers use.
+type Store interface {
+	CreateScan(ctx context.Context, sc store.Scan) (string, error)
+	GetScan(ctx context.Context, id string) (store.Scan, error)
+	ListScansByUser(ctx context.Context, userID string, limit int, before string) ([]store.Scan, error)
+	FindCachedScan(ctx context.Context, optionsHash string, ttl time.Duration) (store.Scan, error)
+	FailScan(ctx context.Context, id, msg string) error
+	ListResults(ctx context.Context, scanID string) ([]store.ResultRow, error)
+	GetArtifact(ctx context.Context, scanID, kind string) ([]byte, error)
+
+	CreateSchedule(ctx context.Context, sc store.Schedule) (string, error)
+	ListSchedulesByUser(ctx context.Context, userID string) ([]store.Schedule, error)
+	SetScheduleEnabled(ctx context.Context, id, userID string, enabled bool) error
+	DeleteSchedule(ctx context.Context, id, userID string) error
+
+	Ping(ctx context.Context) error
+}
+
+// Cache is the part of *cache.Cache the handlers use.
+type Cache interface {
+	GetScanID(ctx context.Context, optionsHash string) (string, bool, error)
+	PutScanID(ctx context.Context, optionsHash, scanID string, ttl time.Duration) error
+	GetProgress(ctx context.Context, scanID string) (done, total int, ok bool)
+	TryAcquire(ctx context.Context, userID string, maxPerUser, maxGlobal int) (bool, error)
+	Release(ctx context.Context, userID string)
+}
+
+// Queue is the part of *queue.Client the handlers use.
+type Queue interface {
+	Enqueue(ctx context.Context, scanID string) error
+	Depth() (int, error)
+}
+
+var (
+	_ Store = (*store.Store)(nil)
+	_ Cache = (*cache.Cache)(nil)
+	_ Queue = (*queue.Client)(nil)
+)
+
 type Server struct {
 	cfg       config.Config
-	store     *store.Store
-	cache     *cache.Cache
-	queue     *queue.Client
+	store     Store
+	cache     Cache
+	queue     Queue
 	templates *template.Template
 	limiter   *rateLimiter
 }
 
-func New(cfg config.Config, st *store.Store, c *cache.Cache, q *queue.Client) (*Server, error) {
+// New wires the handlers to their backends: in production a *store.Store,
+// *cache.Cache and *queue.Client; in tests, in-memory fakes (see servertest).
+func New(cfg config.Config, st Store, c Cache, q Queue) (*Server, error) {
 	tmpl, err := template.New("").Funcs(template.FuncMap{
 		"statusClass": scanStatusClass,
 		"percent": func(done, total int) int {
@@ -62,7 +105,10 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("GET /api/scans/{id}", s.handleGetScan)
 	mux.HandleFunc("GET /api/scans/{id}/results", s.handleGetResults)
 	mux.HandleFunc("GET /api/scans/{id}/sarif", s.handleGetSARIF)
+	mux.HandleFunc("GET /api/scans/{id}/report", s.handleGetReport)
 	mux.HandleFunc("GET /api/scans/{id}/events", s.handleEvents)
+	mux.HandleFunc("GET /api/scans/{id}/har", s.handleGetHAR)
+	mux.HandleFunc("GET /api/lint", s.handleLint)
 
 	mux.HandleFunc("POST /api/schedules", s.handleCreateSchedule)
 	mux.HandleFunc("GET /api/schedules", s.handleListSchedules)
@@ -85,7 +131,7 @@ func (s *Server) Handler() http.Handler {
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	if err := s.store.Pool().Ping(r.Context()); err != nil {
+	if err := s.store.Ping(r.Context()); err != nil {
 		http.Error(w, "db unavailable", http.StatusServiceUnavailable)
 		return
 	}
@@ -0,0 +1,298 @@
+// Package servertest runs the real parserod handlers against an in-memory
+// backend, so API clients can be tested without Postgres or Redis. Nothing
+// scans: a test plays the worker by calling Progress, Complete or Fail.
+package servertest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zvdy/parsero-go/internal/config"
+	"github.com/zvdy/parsero-go/internal/server"
+	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+// IdentityHeader is the trusted identity header the test server reads.
+const IdentityHeader = "X-Auth-Request-Email"
+
+// New starts a parserod API on an httptest server, closed when the test ends.
+func New(t testing.TB) (*httptest.Server, *Backend) {
+	t.Helper()
+	b := NewBackend()
+	cfg := config.Config{
+		ScanCacheTTL:   10 * time.Minute,
+		ScanTimeout:    5 * time.Second,
+		RateLimitRPS:   1000,
+		RateLimitBurst: 1000,
+		IdentityHeader: IdentityHeader,
+		Role:           "web",
+	}
+	srv, err := server.New(cfg, b, b, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts, b
+}
+
+// Backend is an in-memory server.Store, server.Cache and server.Queue.
+type Backend struct {
+	mu        sync.Mutex
+	seq       int
+	now       time.Time
+	scans     map[string]store.Scan
+	results   map[string][]store.ResultRow
+	progress  map[string][2]int
+	schedules map[string]store.Schedule
+	artifacts map[[2]string][]byte
+	queued    []string
+}
+
+var (
+	_ server.Store = (*Backend)(nil)
+	_ server.Cache = (*Backend)(nil)
+	_ server.Queue = (*Backend)(nil)
+)
+
+func NewBackend() *Backend {
+	return &Backend{
+		now:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		scans:     map[string]store.Scan{},
+		results:   map[string][]store.ResultRow{},
+		progress:  map[string][2]int{},
+		schedules: map[string]store.Schedule{},
+		artifacts: map[[2]string][]byte{},
+	}
+}
+
+// next returns a fresh ID and a creation time one second after the last, so
+// listings have a stable order.
+func (b *Backend) next(prefix string) (string, time.Time) {
+	b.seq++
+	b.now = b.now.Add(time.Second)
+	return fmt.Sprintf("%s-%04d", prefix, b.seq), b.now
+}
+
+// Queued returns the IDs of every scan enqueued so far.
+func (b *Backend) Queued() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.queued...)
+}
+
+// Progress records a running scan's progress, as the worker would.
+func (b *Backend) Progress(id string, done, total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc := b.scans[id]
+	sc.Status = "running"
+	b.scans[id] = sc
+	b.progress[id] = [2]int{done, total}
+}
+
+// Complete finishes a scan with rows, filling in the summary counts. The
+// paths of the robots rows become the scan's Disallow list.
+func (b *Backend) Complete(id string, rows []store.ResultRow) {
+	b.complete(id, http.StatusOK, rows)
+}
+
+// NoRobots finishes a scan as the worker does for a target whose robots.txt
+// is a 404: done, with nothing probed.
+func (b *Backend) NoRobots(id string) {
+	b.complete(id, http.StatusNotFound, nil)
+}
+
+func (b *Backend) complete(id string, robotsStatus int, rows []store.ResultRow) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc := b.scans[id]
+	sc.Status, sc.DurationSeconds, sc.TotalPaths = "done", 1.5, len(rows)
+	sc.RobotsStatus = robotsStatus
+	for _, r := range rows {
+		if u, err := url.Parse(r.URL); err == nil && r.Source == scanner.SourceRobots {
+			sc.Disallow = append(sc.Disallow, strings.TrimPrefix(u.Path, "/"))
+		}
+		switch {
+		case r.Error != "":
+			sc.Errors++
+		case r.StatusCode == 200:
+			sc.Status200++
+		default:
+			sc.OtherStatus++
+		}
+	}
+	finished := b.now.Add(time.Second)
+	sc.FinishedAt = &finished
+	b.scans[id] = sc
+	b.results[id] = rows
+}
+
+// Fail ends a scan with msg, as the worker does for a scan that can't run.
+func (b *Backend) Fail(id, msg string) {
+	b.FailScan(context.Background(), id, msg)
+}
+
+// Artifact stores a scan's artifact (e.g. store.ArtifactHAR, gzip'd), as the
+// worker does for recorded scans.
+func (b *Backend) Artifact(id, kind string, data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.artifacts[[2]string{id, kind}] = data
+}
+
+func (b *Backend) CreateScan(_ context.Context, sc store.Scan) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc.ID, sc.CreatedAt = b.next("scan")
+	sc.Status = "queued"
+	b.scans[sc.ID] = sc
+	return sc.ID, nil
+}
+
+func (b *Backend) GetScan(_ context.Context, id string) (store.Scan, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc, ok := b.scans[id]
+	if !ok {
+		return store.Scan{}, store.ErrNotFound
+	}
+	return sc, nil
+}
+
+func (b *Backend) ListScansByUser(_ context.Context, userID string, limit int, before string) ([]store.Scan, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []store.Scan
+	for _, sc := range b.scans {
+		if sc.UserID == userID {
+			out = append(out, sc)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	if before != "" {
+		for i, sc := range out {
+			if sc.ID == before {
+				out = out[i+1:]
+				break
+			}
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// FindCachedScan never hits, so every submission creates a scan.
+func (b *Backend) FindCachedScan(context.Context, string, time.Duration) (store.Scan, error) {
+	return store.Scan{}, store.ErrNotFound
+}
+
+func (b *Backend) FailScan(_ context.Context, id, msg string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc, ok := b.scans[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	sc.Status, sc.ErrorMessage = "failed", msg
+	b.scans[id] = sc
+	return nil
+}
+
+func (b *Backend) ListResults(_ context.Context, scanID string) ([]store.ResultRow, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]store.ResultRow(nil), b.results[scanID]...), nil
+}
+
+func (b *Backend) GetArtifact(_ context.Context, scanID, kind string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.artifacts[[2]string{scanID, kind}]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return data, nil
+}
+
+func (b *Backend) CreateSchedule(_ context.Context, sc store.Schedule) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc.ID, sc.CreatedAt = b.next("schedule")
+	b.schedules[sc.ID] = sc
+	return sc.ID, nil
+}
+
+func (b *Backend) ListSchedulesByUser(_ context.Context, userID string) ([]store.Schedule, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []store.Schedule
+	for _, sc := range b.schedules {
+		if sc.UserID == userID {
+			out = append(out, sc)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (b *Backend) SetScheduleEnabled(_ context.Context, id, userID string, enabled bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sc, ok := b.schedules[id]
+	if !ok || sc.UserID != userID {
+		return store.ErrNotFound
+	}
+	sc.Enabled = enabled
+	b.schedules[id] = sc
+	return nil
+}
+
+func (b *Backend) DeleteSchedule(_ context.Context, id, userID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sc, ok := b.schedules[id]; !ok || sc.UserID != userID {
+		return store.ErrNotFound
+	}
+	delete(b.schedules, id)
+	return nil
+}
+
+func (b *Backend) Ping(context.Context) error { return nil }
+
+// GetScanID never hits; see FindCachedScan.
+func (b *Backend) GetScanID(context.Context, string) (string, bool, error) { return "", false, nil }
+
+func (b *Backend) PutScanID(context.Context, string, string, time.Duration) error { return nil }
+
+func (b *Backend) GetProgress(_ context.Context, scanID string) (done, total int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.progress[scanID]
+	return p[0], p[1], ok
+}
+
+// TryAcquire never throttles.
+func (b *Backend) TryAcquire(context.Context, string, int, int) (bool, error) { return true, nil }
+
+func (b *Backend) Release(context.Context, string) {}
+
+func (b *Backend) Enqueue(_ context.Context, scanID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queued = append(b.queued, scanID)
+	return nil
+}
+
+func (b *Backend) Depth() (int, error) { return 0, nil }
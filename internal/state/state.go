@@ -0,0 +1,192 @@
+// Package state checkpoints a CLI scan to an append-only JSON Lines file, one
+// record per robots.txt fetch, probe result and finished target, so a scan
+// cut short by Ctrl-C or a network drop can resume where it stopped. Each
+// record is a single write, so at worst a crash truncates the last line, and
+// Load ignores that line.
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+const (
+	kindRobots = "robots"
+	kindResult = "result"
+	kindDone   = "done"
+)
+
+type record struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+
+	// robots
+	Status   int      `json:"status,omitempty"`
+	Disallow []string `json:"disallow,omitempty"`
+
+	// result
+	Result *types.Result `json:"result,omitempty"`
+
+	// done
+	Done *Done `json:"done,omitempty"`
+}
+
+// Done is what a finished target needs, beyond its results, to be reported
+// again without rescanning.
+type Done struct {
+	Started      time.Time                 `json:"started"`
+	Duration     time.Duration             `json:"duration"`
+	Error        string                    `json:"error,omitempty"`
+	RobotsStatus int                       `json:"robots_status,omitempty"`
+	Concurrency  *types.ConcurrencyProfile `json:"concurrency,omitempty"`
+}
+
+// Target is one target's checkpointed progress.
+type Target struct {
+	// Fetched is set once robots.txt was read; Disallow is the list the
+	// resumed scan must cover.
+	Fetched      bool
+	RobotsStatus int
+	Disallow     []string
+	Results      []types.Result
+	Done         *Done // nil while the target is unfinished
+}
+
+// Probed reports which result URLs are already checkpointed.
+func (t *Target) Probed() map[string]bool {
+	seen := make(map[string]bool, len(t.Results))
+	for _, r := range t.Results {
+		seen[r.URL] = true
+	}
+	return seen
+}
+
+// Load reads a state file into per-target progress.
+func Load(path string) (map[string]*Target, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	targets := map[string]*Target{}
+	get := func(name string) *Target {
+		t, ok := targets[name]
+		if !ok {
+			t = &Target{}
+			targets[name] = t
+		}
+		return t
+	}
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	line := 0
+	var pending error
+	for sc.Scan() {
+		line++
+		if pending != nil {
+			// A bad line followed by good ones isn't a torn write.
+			return nil, pending
+		}
+		data := bytes.TrimSpace(sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			pending = fmt.Errorf("%s:%d: %w", path, line, err)
+			continue
+		}
+		t := get(rec.Target)
+		switch rec.Type {
+		case kindRobots:
+			t.Fetched, t.RobotsStatus, t.Disallow = true, rec.Status, rec.Disallow
+		case kindResult:
+			if rec.Result != nil {
+				t.Results = append(t.Results, *rec.Result)
+			}
+		case kindDone:
+			t.Done = rec.Done
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown record type %q", path, line, rec.Type)
+		}
+	}
+	return targets, sc.Err()
+}
+
+// Writer appends checkpoint records. It is safe for concurrent use, and keeps
+// the first write error for Close, so callers recording from callbacks can
+// check once at the end.
+type Writer struct {
+	mu  sync.Mutex
+	f   *os.File
+	err error
+}
+
+// Create starts a new state file, replacing any existing one.
+func Create(path string) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{f: f}, nil
+}
+
+// Append continues an existing state file.
+func Append(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{f: f}, nil
+}
+
+// Robots records the Disallow list fetched for target.
+func (w *Writer) Robots(target string, status int, disallow []string) error {
+	return w.write(record{Type: kindRobots, Target: target, Status: status, Disallow: disallow})
+}
+
+// Result records one finished probe.
+func (w *Writer) Result(target string, r types.Result) error {
+	return w.write(record{Type: kindResult, Target: target, Result: &r})
+}
+
+// Done marks target finished.
+func (w *Writer) Done(target string, d Done) error {
+	return w.write(record{Type: kindDone, Target: target, Done: &d})
+}
+
+func (w *Writer) write(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err = w.f.Write(append(data, '\n')); err != nil && w.err == nil {
+		w.err = err
+	}
+	return err
+}
+
+// Close syncs and closes the file, returning the first error seen.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.f.Sync()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	if w.err != nil {
+		return w.err
+	}
+	return err
+}
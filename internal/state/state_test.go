@@ -0,0 +1,93 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state")
+	w, err := Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Robots("a", 200, []string{"x", "y"})
+	w.Result("a", types.Result{URL: "http://a/x", StatusCode: 200})
+	w.Result("a", types.Result{URL: "http://a/y", Error: errors.New("timeout")})
+	w.Done("a", Done{Started: time.Unix(0, 0).UTC(), Duration: time.Second, RobotsStatus: 200})
+	w.Robots("b", 200, []string{"z"})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := got["a"], got["b"]
+	if a == nil || a.Done == nil || len(a.Results) != 2 || a.Done.Duration != time.Second {
+		t.Fatalf("a = %+v", a)
+	}
+	if a.Results[1].Error == nil || a.Results[1].Error.Error() != "timeout" {
+		t.Errorf("probe error not kept: %+v", a.Results[1])
+	}
+	if !a.Probed()["http://a/x"] || a.Probed()["http://a/z"] {
+		t.Errorf("Probed() = %v", a.Probed())
+	}
+	if b == nil || b.Done != nil || !b.Fetched || len(b.Disallow) != 1 {
+		t.Errorf("b = %+v", b)
+	}
+}
+
+func TestLoadTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state")
+	data := `{"type":"robots","target":"a","status":200,"disallow":["x"]}
+{"type":"result","target":"a","result":{"URL":"http://a/x","StatusCode":200}}
+{"type":"result","target":"a","resu`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("torn last line should be ignored: %v", err)
+	}
+	if len(got["a"].Results) != 1 {
+		t.Errorf("results = %+v", got["a"].Results)
+	}
+
+	// Corruption in the middle is an error, not a silent loss.
+	if err := os.WriteFile(path, []byte("garbage\n"+strings.SplitN(data, "\n", 2)[0]+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("corrupt line before valid records accepted")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state")
+	w, _ := Create(path)
+	w.Robots("a", 200, []string{"x"})
+	w.Close()
+
+	w, err := Append(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Result("a", types.Result{URL: "http://a/x", StatusCode: 403})
+	w.Close()
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got["a"].Fetched || len(got["a"].Results) != 1 {
+		t.Errorf("a = %+v", got["a"])
+	}
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ArtifactHAR is the artifact kind of a scan's gzip'd HAR recording.
+const ArtifactHAR = "har"
+
+// PutArtifact stores (or replaces) a scan's artifact of the given kind until
+// expiresAt.
+func (s *Store) PutArtifact(ctx context.Context, scanID, kind string, data []byte, expiresAt time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO scan_artifacts (scan_id, kind, data, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scan_id, kind) DO UPDATE
+		SET data = EXCLUDED.data, created_at = now(), expires_at = EXCLUDED.expires_at`,
+		scanID, kind, data, expiresAt)
+	return err
+}
+
+// GetArtifact returns an unexpired artifact, else ErrNotFound.
+func (s *Store) GetArtifact(ctx context.Context, scanID, kind string) ([]byte, error) {
+	var data []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT data FROM scan_artifacts
+		WHERE scan_id = $1 AND kind = $2 AND expires_at > now()`, scanID, kind,
+	).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// DeleteExpiredArtifacts prunes artifacts past their retention and reports
+// how many went.
+func (s *Store) DeleteExpiredArtifacts(ctx context.Context) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM scan_artifacts WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
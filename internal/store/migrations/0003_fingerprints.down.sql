@@ -0,0 +1,2 @@
+ALTER TABLE schedules DROP COLUMN IF EXISTS alert_fingerprints;
+ALTER TABLE scan_results DROP COLUMN IF EXISTS fingerprints;
@@ -0,0 +1,5 @@
+-- Technology fingerprints per probed path, plus per-monitor fingerprint alert
+-- filters ("alert when any Jenkins becomes reachable").
+
+ALTER TABLE scan_results ADD COLUMN IF NOT EXISTS fingerprints TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS alert_fingerprints TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+ALTER TABLE scan_results DROP COLUMN IF EXISTS auth_scheme;
+ALTER TABLE scan_results DROP COLUMN IF EXISTS class;
@@ -0,0 +1,6 @@
+-- Exposure classification per probed path: open, auth (with scheme), login,
+-- forbidden, waf, not_found or other. NULL for rows recorded before this
+-- migration; readers fall back to the status code.
+
+ALTER TABLE scan_results ADD COLUMN IF NOT EXISTS class TEXT;
+ALTER TABLE scan_results ADD COLUMN IF NOT EXISTS auth_scheme TEXT;
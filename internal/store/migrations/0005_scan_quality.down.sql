@@ -0,0 +1,2 @@
+ALTER TABLE scans DROP COLUMN IF EXISTS degraded_reason;
+ALTER TABLE scans DROP COLUMN IF EXISTS degraded;
@@ -0,0 +1,5 @@
+-- Scan quality: a scan whose probes were mostly WAF/bot blocks, or that shifted
+-- to one uniform status, is flagged degraded and excluded from monitor diffs.
+
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS degraded_reason TEXT;
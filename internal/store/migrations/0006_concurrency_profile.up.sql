@@ -0,0 +1,4 @@
+-- The worker-pool profile a scan ran with (fixed, or the range an adaptive
+-- controller moved through), for tuning against fragile targets and CDNs.
+
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS concurrency_profile JSONB;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scan_artifacts;
+ALTER TABLE scans DROP COLUMN IF EXISTS record_har;
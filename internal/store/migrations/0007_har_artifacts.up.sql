@@ -0,0 +1,15 @@
+-- Opt-in HAR recordings: every HTTP exchange of a scan, stored gzip'd as a
+-- per-scan artifact and pruned once expires_at passes.
+
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS record_har BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS scan_artifacts (
+    scan_id    UUID NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+    kind       TEXT NOT NULL,
+    data       BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scan_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scan_artifacts_expires ON scan_artifacts (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE scans DROP COLUMN IF EXISTS disallow;
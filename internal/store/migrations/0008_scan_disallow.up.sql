@@ -0,0 +1,4 @@
+-- The robots.txt Disallow entries a scan probed, so report downloads carry
+-- them for verify and diff.
+
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS disallow TEXT[] NOT NULL DEFAULT '{}';
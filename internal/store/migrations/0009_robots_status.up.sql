@@ -0,0 +1,4 @@
+-- The HTTP status of a scan's robots.txt fetch, so clients can tell a site
+-- without one (404) from one listing nothing. NULL when it came from cache.
+
+ALTER TABLE scans ADD COLUMN IF NOT EXISTS robots_status INTEGER;
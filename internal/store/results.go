@@ -13,14 +13,15 @@ func (s *Store) InsertResults(ctx context.Context, scanID string, rows []ResultR
 	}
 	_, err := s.pool.CopyFrom(ctx,
 		pgx.Identifier{"scan_results"},
-		[]string{"scan_id", "url", "status_code", "status", "error", "source"},
+		[]string{"scan_id", "url", "status_code", "status", "error", "source", "fingerprints", "class", "auth_scheme"},
 		pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
 			r := rows[i]
 			var code any
 			if r.StatusCode != 0 {
 				code = r.StatusCode
 			}
-			return []any{scanID, r.URL, code, r.Status, nullify(r.Error), r.Source}, nil
+			return []any{scanID, r.URL, code, r.Status, nullify(r.Error), r.Source, nonNil(r.Fingerprints),
+				nullify(r.Class), nullify(r.AuthScheme)}, nil
 		}),
 	)
 	return err
@@ -29,7 +30,8 @@ func (s *Store) InsertResults(ctx context.Context, scanID string, rows []ResultR
 func (s *Store) ListResults(ctx context.Context, scanID string) ([]ResultRow, error) {
 	rows, err := s.pool.Query(ctx, `
 		SELECT url, COALESCE(status_code, 0), COALESCE(status, ''),
-		       COALESCE(error, ''), source
+		       COALESCE(error, ''), source, fingerprints,
+		       COALESCE(class, ''), COALESCE(auth_scheme, '')
 		FROM scan_results WHERE scan_id = $1 ORDER BY id`, scanID)
 	if err != nil {
 		return nil, err
@@ -39,7 +41,7 @@ func (s *Store) ListResults(ctx context.Context, scanID string) ([]ResultRow, er
 	var out []ResultRow
 	for rows.Next() {
 		var r ResultRow
-		if err := rows.Scan(&r.URL, &r.StatusCode, &r.Status, &r.Error, &r.Source); err != nil {
+		if err := rows.Scan(&r.URL, &r.StatusCode, &r.Status, &r.Error, &r.Source, &r.Fingerprints, &r.Class, &r.AuthScheme); err != nil {
 			return nil, err
 		}
 		out = append(out, r)
@@ -47,6 +49,14 @@ func (s *Store) ListResults(ctx context.Context, scanID string) ([]ResultRow, er
 	return out, rows.Err()
 }
 
+// nonNil stores a nil slice as an empty array, since TEXT[] columns are NOT NULL.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
 // nullify stores an empty string as SQL NULL.
 func nullify(s string) any {
 	if s == "" {
@@ -54,3 +64,11 @@ func nullify(s string) any {
 	}
 	return s
 }
+
+// nullifyInt stores a zero int as SQL NULL.
+func nullifyInt(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
@@ -15,31 +15,33 @@ func (s *Store) CreateScan(ctx context.Context, sc Scan) (string, error) {
 	}
 	var id string
 	err := s.pool.QueryRow(ctx, `
-		INSERT INTO scans (user_id, target, options_hash, only200, search_bing, status, schedule_id, trigger)
-		VALUES ($1, $2, $3, $4, $5, 'queued', $6, $7)
+		INSERT INTO scans (user_id, target, options_hash, only200, search_bing, status, schedule_id, trigger, record_har)
+		VALUES ($1, $2, $3, $4, $5, 'queued', $6, $7, $8)
 		RETURNING id`,
-		sc.UserID, sc.Target, sc.OptionsHash, sc.Only200, sc.SearchBing, sc.ScheduleID, trigger,
+		sc.UserID, sc.Target, sc.OptionsHash, sc.Only200, sc.SearchBing, sc.ScheduleID, trigger, sc.RecordHAR,
 	).Scan(&id)
 	return id, err
 }
 
-// PreviousDoneScan returns the prior completed scan for the same options_hash
-// (excluding excludeID), for diffing. ErrNotFound when there's no prior scan.
+// PreviousDoneScan returns the prior completed, non-degraded scan for the same
+// options_hash (excluding excludeID): the baseline for diffing and for spotting
+// a sudden status shift. ErrNotFound when there's no prior scan.
 func (s *Store) PreviousDoneScan(ctx context.Context, optionsHash, excludeID string) (Scan, error) {
 	var sc Scan
 	err := s.pool.QueryRow(ctx, `
 		SELECT id, user_id, target, options_hash, only200, search_bing, status,
 		       COALESCE(duration_seconds, 0), total_paths, status_200, other_status,
 		       errors, COALESCE(error_message, ''), created_at, started_at, finished_at,
-		       COALESCE(trigger, 'manual')
+		       COALESCE(trigger, 'manual'), degraded, COALESCE(degraded_reason, '')
 		FROM scans
-		WHERE options_hash = $1 AND status = 'done' AND id <> $2
+		WHERE options_hash = $1 AND status = 'done' AND id <> $2 AND NOT degraded
 		ORDER BY finished_at DESC LIMIT 1`,
 		optionsHash, excludeID,
 	).Scan(
 		&sc.ID, &sc.UserID, &sc.Target, &sc.OptionsHash, &sc.Only200, &sc.SearchBing,
 		&sc.Status, &sc.DurationSeconds, &sc.TotalPaths, &sc.Status200, &sc.OtherStatus,
 		&sc.Errors, &sc.ErrorMessage, &sc.CreatedAt, &sc.StartedAt, &sc.FinishedAt, &sc.Trigger,
+		&sc.Degraded, &sc.DegradedReason,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Scan{}, ErrNotFound
@@ -53,13 +55,15 @@ func (s *Store) GetScan(ctx context.Context, id string) (Scan, error) {
 		SELECT id, user_id, target, options_hash, only200, search_bing, status,
 		       COALESCE(duration_seconds, 0), total_paths, status_200, other_status,
 		       errors, COALESCE(error_message, ''), created_at, started_at, finished_at,
-		       schedule_id, COALESCE(trigger, 'manual')
+		       schedule_id, COALESCE(trigger, 'manual'), degraded, COALESCE(degraded_reason, ''),
+		       concurrency_profile, record_har, disallow, COALESCE(robots_status, 0)
 		FROM scans WHERE id = $1`, id,
 	).Scan(
 		&sc.ID, &sc.UserID, &sc.Target, &sc.OptionsHash, &sc.Only200, &sc.SearchBing,
 		&sc.Status, &sc.DurationSeconds, &sc.TotalPaths, &sc.Status200, &sc.OtherStatus,
 		&sc.Errors, &sc.ErrorMessage, &sc.CreatedAt, &sc.StartedAt, &sc.FinishedAt,
-		&sc.ScheduleID, &sc.Trigger,
+		&sc.ScheduleID, &sc.Trigger, &sc.Degraded, &sc.DegradedReason, &sc.Concurrency,
+		&sc.RecordHAR, &sc.Disallow, &sc.RobotsStatus,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Scan{}, ErrNotFound
@@ -67,15 +71,21 @@ func (s *Store) GetScan(ctx context.Context, id string) (Scan, error) {
 	return sc, err
 }
 
-func (s *Store) ListScansByUser(ctx context.Context, userID string, limit int) ([]Scan, error) {
+// ListScansByUser returns userID's scans, newest first. A non-empty before
+// (a scan ID from a previous page) starts the page after that scan.
+func (s *Store) ListScansByUser(ctx context.Context, userID string, limit int, before string) ([]Scan, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 	rows, err := s.pool.Query(ctx, `
 		SELECT id, user_id, target, options_hash, only200, search_bing, status,
 		       COALESCE(duration_seconds, 0), total_paths, status_200, other_status,
-		       errors, COALESCE(error_message, ''), created_at, started_at, finished_at
-		FROM scans WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2`, userID, limit)
+		       errors, COALESCE(error_message, ''), created_at, started_at, finished_at,
+		       degraded, COALESCE(degraded_reason, ''), record_har, COALESCE(robots_status, 0)
+		FROM scans
+		WHERE user_id = $1 AND (NULLIF($3, '') IS NULL OR
+		      (created_at, id) < (SELECT created_at, id FROM scans WHERE id = NULLIF($3, '')::uuid))
+		ORDER BY created_at DESC, id DESC LIMIT $2`, userID, limit, before)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +98,7 @@ func (s *Store) ListScansByUser(ctx context.Context, userID string, limit int) (
 			&sc.ID, &sc.UserID, &sc.Target, &sc.OptionsHash, &sc.Only200, &sc.SearchBing,
 			&sc.Status, &sc.DurationSeconds, &sc.TotalPaths, &sc.Status200, &sc.OtherStatus,
 			&sc.Errors, &sc.ErrorMessage, &sc.CreatedAt, &sc.StartedAt, &sc.FinishedAt,
+			&sc.Degraded, &sc.DegradedReason, &sc.RecordHAR, &sc.RobotsStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -103,7 +114,8 @@ func (s *Store) FindCachedScan(ctx context.Context, optionsHash string, ttl time
 	err := s.pool.QueryRow(ctx, `
 		SELECT id, user_id, target, options_hash, only200, search_bing, status,
 		       COALESCE(duration_seconds, 0), total_paths, status_200, other_status,
-		       errors, COALESCE(error_message, ''), created_at, started_at, finished_at
+		       errors, COALESCE(error_message, ''), created_at, started_at, finished_at,
+		       degraded, COALESCE(degraded_reason, ''), COALESCE(robots_status, 0)
 		FROM scans
 		WHERE options_hash = $1 AND status = 'done' AND finished_at > now() - $2::interval
 		ORDER BY finished_at DESC LIMIT 1`,
@@ -112,6 +124,7 @@ func (s *Store) FindCachedScan(ctx context.Context, optionsHash string, ttl time
 		&sc.ID, &sc.UserID, &sc.Target, &sc.OptionsHash, &sc.Only200, &sc.SearchBing,
 		&sc.Status, &sc.DurationSeconds, &sc.TotalPaths, &sc.Status200, &sc.OtherStatus,
 		&sc.Errors, &sc.ErrorMessage, &sc.CreatedAt, &sc.StartedAt, &sc.FinishedAt,
+		&sc.Degraded, &sc.DegradedReason, &sc.RobotsStatus,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Scan{}, ErrNotFound
@@ -132,9 +145,13 @@ func (s *Store) CompleteScan(ctx context.Context, id string, sc Scan) error {
 	_, err := s.pool.Exec(ctx, `
 		UPDATE scans
 		SET status = 'done', finished_at = now(), duration_seconds = $2,
-		    total_paths = $3, status_200 = $4, other_status = $5, errors = $6
+		    total_paths = $3, status_200 = $4, other_status = $5, errors = $6,
+		    degraded = $7, degraded_reason = $8, concurrency_profile = $9,
+		    disallow = $10, robots_status = $11
 		WHERE id = $1`,
-		id, sc.DurationSeconds, sc.TotalPaths, sc.Status200, sc.OtherStatus, sc.Errors)
+		id, sc.DurationSeconds, sc.TotalPaths, sc.Status200, sc.OtherStatus, sc.Errors,
+		sc.Degraded, nullify(sc.DegradedReason), sc.Concurrency, nonNil(sc.Disallow),
+		nullifyInt(sc.RobotsStatus))
 	return err
 }
 
@@ -19,30 +19,34 @@ type Schedule struct {
 	Enabled        bool
 	NotifyWebhook  string
 	NotifyOnChange bool
-	CreatedAt      time.Time
-	LastRunAt      *time.Time
+	// AlertFingerprints, when set, restricts alerts to newly reachable paths
+	// fingerprinted as one of these technologies.
+	AlertFingerprints []string
+	CreatedAt         time.Time
+	LastRunAt         *time.Time
 }
 
 func (s *Store) CreateSchedule(ctx context.Context, sc Schedule) (string, error) {
 	var id string
 	err := s.pool.QueryRow(ctx, `
-		INSERT INTO schedules (user_id, target, options_hash, only200, search_bing, cron, enabled, notify_webhook, notify_on_change)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO schedules (user_id, target, options_hash, only200, search_bing, cron, enabled, notify_webhook, notify_on_change, alert_fingerprints)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id`,
 		sc.UserID, sc.Target, sc.OptionsHash, sc.Only200, sc.SearchBing, sc.Cron,
-		sc.Enabled, nullify(sc.NotifyWebhook), sc.NotifyOnChange,
+		sc.Enabled, nullify(sc.NotifyWebhook), sc.NotifyOnChange, nonNil(sc.AlertFingerprints),
 	).Scan(&id)
 	return id, err
 }
 
 const scheduleCols = `id, user_id, target, options_hash, only200, search_bing, cron,
-	enabled, COALESCE(notify_webhook, ''), notify_on_change, created_at, last_run_at`
+	enabled, COALESCE(notify_webhook, ''), notify_on_change, alert_fingerprints, created_at, last_run_at`
 
 func scanSchedule(row pgx.Row) (Schedule, error) {
 	var sc Schedule
 	err := row.Scan(
 		&sc.ID, &sc.UserID, &sc.Target, &sc.OptionsHash, &sc.Only200, &sc.SearchBing,
-		&sc.Cron, &sc.Enabled, &sc.NotifyWebhook, &sc.NotifyOnChange, &sc.CreatedAt, &sc.LastRunAt,
+		&sc.Cron, &sc.Enabled, &sc.NotifyWebhook, &sc.NotifyOnChange, &sc.AlertFingerprints,
+		&sc.CreatedAt, &sc.LastRunAt,
 	)
 	return sc, err
 }
@@ -17,6 +17,7 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // registers the pgx5:// scheme
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zvdy/parsero-go/pkg/types"
 )
 
 //go:embed migrations/*.sql
@@ -48,6 +49,8 @@ func (s *Store) Close() { s.pool.Close() }
 
 func (s *Store) Pool() *pgxpool.Pool { return s.pool }
 
+func (s *Store) Ping(ctx context.Context) error { return s.pool.Ping(ctx) }
+
 // Migrate applies embedded up-migrations under an advisory lock, so it's safe to
 // run on every instance at startup.
 func (s *Store) Migrate(databaseURL string) error {
@@ -104,12 +107,26 @@ type Scan struct {
 	FinishedAt      *time.Time
 	ScheduleID      *string
 	Trigger         string
+	// Degraded scans (WAF blocks, uniform status shifts) aren't diffed.
+	Degraded       bool
+	DegradedReason string
+	// Concurrency is nil for scans that haven't completed.
+	Concurrency *types.ConcurrencyProfile
+	// RecordHAR asks the worker to store a HAR artifact of the scan's traffic.
+	RecordHAR bool
+	// Disallow is the robots.txt entry list the results were probed from.
+	Disallow []string
+	// RobotsStatus is the robots.txt HTTP status; 0 if it came from cache.
+	RobotsStatus int
 }
 
 type ResultRow struct {
-	URL        string
-	StatusCode int
-	Status     string
-	Error      string
-	Source     string
+	URL          string
+	StatusCode   int
+	Status       string
+	Error        string
+	Source       string
+	Fingerprints []string
+	Class        string
+	AuthScheme   string
 }
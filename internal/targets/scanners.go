@@ -0,0 +1,188 @@
+package targets
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+type inputKind int
+
+const (
+	plainList inputKind = iota
+	nmapXML
+	grepable    // nmap -oG and masscan -oG
+	masscanList // masscan -oL
+)
+
+// sniff recognises scanner output from its first meaningful line.
+func sniff(data []byte) inputKind {
+	head := bytes.TrimSpace(data)
+	if bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<nmaprun")) {
+		return nmapXML
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "# Nmap"), strings.HasPrefix(line, "# Masscan"):
+			return grepable
+		case strings.HasPrefix(line, "#masscan"):
+			return masscanList
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "Host: ") && strings.Contains(line, "Ports: "):
+			return grepable
+		case strings.HasPrefix(line, "open tcp "):
+			return masscanList
+		}
+		return plainList
+	}
+	return plainList
+}
+
+// webPorts are probed even when the scanner didn't name the service.
+var webPorts = map[int]bool{80: true, 443: true, 8000: true, 8008: true, 8080: true, 8443: true, 8888: true}
+
+// webTarget turns an open port into a target, or "" if it isn't web-facing.
+func webTarget(host string, port int, service string) string {
+	service = strings.ToLower(service)
+	tls := strings.Contains(service, "https") || strings.Contains(service, "ssl") || port == 443 || port == 8443
+	if !strings.Contains(service, "http") && !webPorts[port] {
+		return ""
+	}
+	scheme := "http"
+	if tls {
+		scheme = "https"
+	}
+	t, err := Parse(scheme + "://" + joinHost(host) + ":" + strconv.Itoa(port))
+	if err != nil {
+		return ""
+	}
+	return t
+}
+
+func joinHost(host string) string {
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// readGrepable parses lines like
+//
+//	Host: 10.0.0.1 (web)	Ports: 80/open/tcp//http///, 22/open/tcp//ssh///
+func readGrepable(data []byte) []string {
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "Host: ") {
+			continue
+		}
+		_, ports, ok := strings.Cut(line, "Ports: ")
+		if !ok {
+			continue
+		}
+		host, _, _ := strings.Cut(strings.TrimPrefix(line, "Host: "), " ")
+		if i := strings.Index(ports, "\t"); i >= 0 {
+			ports = ports[:i] // drop "Ignored State:" and friends
+		}
+		for _, p := range strings.Split(ports, ",") {
+			f := strings.Split(strings.TrimSpace(p), "/")
+			if len(f) < 5 || f[1] != "open" || f[2] != "tcp" {
+				continue
+			}
+			port, err := strconv.Atoi(f[0])
+			if err != nil {
+				continue
+			}
+			if t := webTarget(host, port, f[4]); t != "" {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// readMasscanList parses masscan -oL lines: "open tcp 80 10.0.0.1 1690000000".
+func readMasscanList(data []byte) []string {
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 4 || f[0] != "open" || f[1] != "tcp" {
+			continue
+		}
+		port, err := strconv.Atoi(f[2])
+		if err != nil {
+			continue
+		}
+		if t := webTarget(f[3], port, ""); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+type xmlRun struct {
+	Hosts []struct {
+		Addresses []struct {
+			Addr     string `xml:"addr,attr"`
+			AddrType string `xml:"addrtype,attr"`
+		} `xml:"address"`
+		Hostnames []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"hostnames>hostname"`
+		Ports []struct {
+			Protocol string `xml:"protocol,attr"`
+			PortID   int    `xml:"portid,attr"`
+			State    struct {
+				State string `xml:"state,attr"`
+			} `xml:"state"`
+			Service struct {
+				Name   string `xml:"name,attr"`
+				Tunnel string `xml:"tunnel,attr"`
+			} `xml:"service"`
+		} `xml:"ports>port"`
+	} `xml:"host"`
+}
+
+// readXML parses nmap -oX and masscan -oX output, which share a layout.
+// Hostnames are preferred over addresses so virtual hosts answer correctly.
+func readXML(data []byte) ([]string, error) {
+	var run xmlRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, h := range run.Hosts {
+		host := ""
+		if len(h.Hostnames) > 0 {
+			host = h.Hostnames[0].Name
+		}
+		for _, a := range h.Addresses {
+			if host == "" && a.AddrType != "mac" {
+				host = a.Addr
+			}
+		}
+		if host == "" {
+			continue
+		}
+		for _, p := range h.Ports {
+			if p.Protocol != "tcp" || p.State.State != "open" {
+				continue
+			}
+			service := p.Service.Name
+			if p.Service.Tunnel == "ssl" {
+				service = "ssl/" + service
+			}
+			if t := webTarget(host, p.PortID, service); t != "" {
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package targets
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"path"
+	"strings"
+)
+
+// Scope decides which targets an engagement allows. Patterns are host globs
+// ("*.example.com", "example.com"), IP addresses or CIDR ranges; ports and
+// schemes are ignored. An empty Include allows every host, and Exclude wins
+// over Include.
+type Scope struct {
+	include, exclude []pattern
+}
+
+type pattern struct {
+	glob   string
+	prefix netip.Prefix // valid for IP and CIDR patterns
+}
+
+// NewScope compiles include and exclude patterns.
+func NewScope(include, exclude []string) (Scope, error) {
+	var s Scope
+	var err error
+	if s.include, err = compile(include); err != nil {
+		return Scope{}, err
+	}
+	if s.exclude, err = compile(exclude); err != nil {
+		return Scope{}, err
+	}
+	return s, nil
+}
+
+func compile(raw []string) ([]pattern, error) {
+	out := make([]pattern, 0, len(raw))
+	for _, r := range raw {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if strings.HasPrefix(r, "[") && strings.HasSuffix(r, "]") {
+			r = r[1 : len(r)-1] // bracketed IPv6
+		}
+		if prefix, err := netip.ParsePrefix(r); err == nil {
+			out = append(out, pattern{prefix: prefix.Masked()})
+			continue
+		}
+		if addr, err := netip.ParseAddr(r); err == nil {
+			out = append(out, pattern{prefix: netip.PrefixFrom(addr, addr.BitLen())})
+			continue
+		}
+		if _, err := path.Match(r, ""); err != nil || r == "" {
+			return nil, fmt.Errorf("invalid scope pattern %q", r)
+		}
+		out = append(out, pattern{glob: r})
+	}
+	return out, nil
+}
+
+// Allows reports whether target, as returned by Parse, is in scope.
+func (s Scope) Allows(target string) bool {
+	host := hostOf(target)
+	if matchAny(s.exclude, host) {
+		return false
+	}
+	return len(s.include) == 0 || matchAny(s.include, host)
+}
+
+func matchAny(patterns []pattern, host string) bool {
+	addr, err := netip.ParseAddr(host)
+	isIP := err == nil
+	for _, p := range patterns {
+		if p.prefix.IsValid() {
+			if isIP && p.prefix.Contains(addr.Unmap()) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(p.glob, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOf(target string) string {
+	t := target
+	if i := strings.Index(t, "://"); i >= 0 {
+		t = t[i+3:]
+	}
+	if i := strings.IndexAny(t, "/?#"); i >= 0 {
+		t = t[:i]
+	}
+	if h, _, err := net.SplitHostPort(t); err == nil {
+		t = h
+	}
+	return strings.ToLower(strings.Trim(t, "[]"))
+}
@@ -0,0 +1,214 @@
+// Package targets turns user-supplied target lists — plain host lists, stdin,
+// CIDR ranges, and nmap or masscan output — into normalized, de-duplicated
+// scan targets. Unlike safety.NormalizeTarget it keeps the scheme and port and
+// applies no deny-list: the CLI legitimately scans internal hosts.
+package targets
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxCIDRHosts caps how many addresses one CIDR line may expand to.
+const DefaultMaxCIDRHosts = 1 << 16
+
+type Options struct {
+	// ExpandCIDR turns lines like 10.0.0.0/24 into one target per address;
+	// without it such lines are skipped as invalid.
+	ExpandCIDR   bool
+	MaxCIDRHosts int // default DefaultMaxCIDRHosts
+}
+
+// LineError is an input line that couldn't be turned into a target.
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d %q: %v", e.Line, e.Text, e.Err)
+}
+
+// Parse normalizes one target. The scheme (http or https) and port are kept,
+// path, query and userinfo dropped, and the host lowercased. Plain http on
+// the default port is returned as a bare host, so "http://Example.com/x" and
+// "example.com" are the same target; anything else keeps its URL form, e.g.
+// "https://example.com" or "http://example.com:8080".
+func Parse(raw string) (string, error) {
+	t := strings.TrimSpace(raw)
+	if t == "" {
+		return "", fmt.Errorf("empty target")
+	}
+
+	scheme := "http"
+	if i := strings.Index(t, "://"); i >= 0 {
+		scheme = strings.ToLower(t[:i])
+		t = t[i+3:]
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q", scheme)
+	}
+	if i := strings.IndexAny(t, "/?#"); i >= 0 {
+		t = t[:i]
+	}
+	if i := strings.LastIndex(t, "@"); i >= 0 {
+		t = t[i+1:]
+	}
+
+	host, port := t, ""
+	if h, p, err := net.SplitHostPort(t); err == nil {
+		host, port = h, p
+	} else if strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]") {
+		host = t[1 : len(t)-1]
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return "", fmt.Errorf("empty host")
+	}
+	if strings.ContainsAny(host, " \t,;") {
+		return "", fmt.Errorf("invalid host %q", host)
+	}
+	if port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("invalid port %q", port)
+		}
+		if (scheme == "http" && n == 80) || (scheme == "https" && n == 443) {
+			port = ""
+		}
+	}
+	return format(scheme, host, port), nil
+}
+
+func format(scheme, host, port string) string {
+	hostport := host
+	if strings.Contains(host, ":") {
+		hostport = "[" + host + "]" // IPv6 literal
+	}
+	if port != "" {
+		hostport = net.JoinHostPort(host, port)
+	}
+	if scheme == "http" && port == "" {
+		return hostport
+	}
+	return scheme + "://" + hostport
+}
+
+// Load reads targets from path, or from stdin when path is "-".
+func Load(path string, opts Options) ([]string, []LineError, error) {
+	if path == "-" {
+		return Read(os.Stdin, opts)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return Read(f, opts)
+}
+
+// Read parses a target list. nmap and masscan output (grepable, XML, and
+// masscan's -oL list) is recognised automatically and expanded to one target
+// per open web port; anything else is read as one target per line, with
+// blank lines and #-comments ignored. Duplicates are dropped, keeping the
+// first occurrence's position.
+func Read(r io.Reader, opts Options) ([]string, []LineError, error) {
+	if opts.MaxCIDRHosts <= 0 {
+		opts.MaxCIDRHosts = DefaultMaxCIDRHosts
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		raw     []string
+		skipped []LineError
+	)
+	switch sniff(data) {
+	case nmapXML:
+		if raw, err = readXML(data); err != nil {
+			return nil, nil, err
+		}
+	case grepable:
+		raw = readGrepable(data)
+	case masscanList:
+		raw = readMasscanList(data)
+	default:
+		raw, skipped = readPlain(data, opts)
+	}
+
+	seen := make(map[string]bool, len(raw))
+	out := make([]string, 0, len(raw))
+	for _, t := range raw {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out, skipped, nil
+}
+
+func readPlain(data []byte, opts Options) ([]string, []LineError) {
+	var (
+		out     []string
+		skipped []LineError
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(line); err == nil {
+			if !opts.ExpandCIDR {
+				skipped = append(skipped, LineError{n, line, fmt.Errorf("CIDR range (enable CIDR expansion to scan it)")})
+				continue
+			}
+			hosts, err := expandCIDR(prefix, opts.MaxCIDRHosts)
+			if err != nil {
+				skipped = append(skipped, LineError{n, line, err})
+				continue
+			}
+			out = append(out, hosts...)
+			continue
+		}
+		t, err := Parse(line)
+		if err != nil {
+			skipped = append(skipped, LineError{n, line, err})
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, skipped
+}
+
+// expandCIDR lists every address in prefix, skipping the network and
+// broadcast addresses of IPv4 ranges wider than /31.
+func expandCIDR(prefix netip.Prefix, max int) ([]string, error) {
+	prefix = prefix.Masked()
+	bits := prefix.Addr().BitLen() - prefix.Bits()
+	if bits >= 31 || 1<<bits > max {
+		return nil, fmt.Errorf("range has more than %d addresses", max)
+	}
+	var out []string
+	for a := prefix.Addr(); prefix.Contains(a); a = a.Next() {
+		out = append(out, format("http", a.String(), ""))
+		if !a.Next().IsValid() {
+			break
+		}
+	}
+	if prefix.Addr().Is4() && bits > 1 {
+		out = out[1 : len(out)-1]
+	}
+	return out, nil
+}
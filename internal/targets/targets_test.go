@@ -0,0 +1,140 @@
+package targets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"example.com":                      "example.com",
+		"http://Example.COM/robots.txt":    "example.com",
+		"http://example.com:80":            "example.com",
+		"https://example.com:443/x?y":      "https://example.com",
+		"https://user:pw@example.com:8443": "https://example.com:8443",
+		"example.com:8080":                 "http://example.com:8080",
+		"[2001:db8::1]:8080":               "http://[2001:db8::1]:8080",
+		"https://[2001:db8::1]":            "https://[2001:db8::1]",
+	}
+	for in, want := range cases {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "ftp://example.com", "example.com:0", "example.com:http", "a b.com"} {
+		if got, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) = %q, want error", bad, got)
+		}
+	}
+}
+
+func TestReadPlain(t *testing.T) {
+	in := `# production hosts
+example.com
+https://example.com/   # tls
+http://example.com/admin
+
+ftp://nope
+EXAMPLE.com
+10.0.0.0/30
+`
+	got, skipped, err := Read(strings.NewReader(in), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "example.com,https://example.com" {
+		t.Errorf("targets = %v", got)
+	}
+	if len(skipped) != 2 || skipped[0].Line != 6 || skipped[1].Line != 8 {
+		t.Errorf("skipped = %v, want lines 6 (scheme) and 8 (CIDR)", skipped)
+	}
+
+	got, _, _ = Read(strings.NewReader(in), Options{ExpandCIDR: true})
+	if strings.Join(got[2:], ",") != "10.0.0.1,10.0.0.2" {
+		t.Errorf("expanded CIDR = %v", got[2:])
+	}
+	if _, skipped, _ := Read(strings.NewReader("10.0.0.0/8"), Options{ExpandCIDR: true, MaxCIDRHosts: 256}); len(skipped) != 1 {
+		t.Error("expected oversized CIDR to be skipped")
+	}
+}
+
+func TestReadNmapGrepable(t *testing.T) {
+	in := "# Nmap 7.94 scan initiated as: nmap -oG - 10.0.0.0/30\n" +
+		"Host: 10.0.0.1 (web)\tStatus: Up\n" +
+		"Host: 10.0.0.1 (web)\tPorts: 22/open/tcp//ssh///, 80/open/tcp//http///, 8443/open/tcp//https-alt///, 81/closed/tcp//hosts2-ns///\tIgnored State: closed (996)\n" +
+		"# Nmap done\n"
+	got, _, err := Read(strings.NewReader(in), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "10.0.0.1,https://10.0.0.1:8443" {
+		t.Errorf("targets = %v", got)
+	}
+}
+
+func TestReadMasscan(t *testing.T) {
+	in := "#masscan\nopen tcp 80 10.0.0.5 1690000000\nopen tcp 443 10.0.0.5 1690000000\nopen tcp 22 10.0.0.5 1690000000\n# end\n"
+	got, _, err := Read(strings.NewReader(in), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "10.0.0.5,https://10.0.0.5" {
+		t.Errorf("targets = %v", got)
+	}
+}
+
+func TestReadNmapXML(t *testing.T) {
+	in := `<?xml version="1.0"?>
+<nmaprun>
+  <host>
+    <address addr="10.0.0.9" addrtype="ipv4"/>
+    <hostnames><hostname name="intranet.example.com"/></hostnames>
+    <ports>
+      <port protocol="tcp" portid="8080"><state state="open"/><service name="http-proxy"/></port>
+      <port protocol="tcp" portid="9443"><state state="open"/><service name="http" tunnel="ssl"/></port>
+      <port protocol="tcp" portid="5432"><state state="open"/><service name="postgresql"/></port>
+    </ports>
+  </host>
+</nmaprun>`
+	got, _, err := Read(strings.NewReader(in), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "http://intranet.example.com:8080,https://intranet.example.com:9443" {
+		t.Errorf("targets = %v", got)
+	}
+}
+
+func TestScope(t *testing.T) {
+	s, err := NewScope(
+		[]string{"*.example.com", "example.com", "10.0.0.0/24"},
+		[]string{"legacy.example.com", "10.0.0.13"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]bool{
+		"example.com":                  true,
+		"https://api.example.com:8443": true,
+		"http://LEGACY.example.com":    false,
+		"example.org":                  false,
+		"notexample.com":               false,
+		"10.0.0.7":                     true,
+		"http://10.0.0.13:8080":        false,
+		"10.0.1.1":                     false,
+	}
+	for target, want := range cases {
+		if got := s.Allows(target); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", target, got, want)
+		}
+	}
+
+	open, _ := NewScope(nil, []string{"[2001:db8::1]"})
+	if open.Allows("https://[2001:db8::1]") || !open.Allows("example.com") {
+		t.Error("empty include should allow everything not excluded")
+	}
+	if _, err := NewScope([]string{"[bad"}, nil); err == nil {
+		t.Error("malformed glob accepted")
+	}
+}
@@ -0,0 +1,458 @@
+// Package tui is the interactive scan view behind `parsero scan --tui`: a
+// live progress bar per target, a running status histogram, and a scrollable,
+// filterable list of results. The scan runs in the background and reports to
+// the view through a Feed.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zvdy/parsero-go/pkg/colors"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// Target states.
+const (
+	stateQueued     = "queued"
+	stateRunning    = "running"
+	stateCancelling = "cancelling"
+	stateCancelled  = "cancelled"
+	stateDone       = "done"
+	stateFailed     = "failed"
+)
+
+// Messages a Feed sends to the Model.
+type (
+	startedMsg struct {
+		target string
+		cancel context.CancelFunc
+	}
+	progressMsg struct {
+		target      string
+		done, total int
+	}
+	resultMsg struct {
+		target string
+		result types.Result
+	}
+	finishedMsg struct {
+		target    string
+		err       error
+		cancelled bool
+	}
+	allDoneMsg struct{}
+)
+
+// Feed reports scan events to a running view. It is safe for concurrent use,
+// and a nil Feed drops everything.
+type Feed struct {
+	send func(tea.Msg)
+}
+
+// Started marks target running; cancel is called when the user cancels it.
+func (f *Feed) Started(target string, cancel context.CancelFunc) {
+	if f != nil {
+		f.send(startedMsg{target, cancel})
+	}
+}
+
+// Progress is Scanner.OnProgress for target.
+func (f *Feed) Progress(target string, done, total int) {
+	if f != nil {
+		f.send(progressMsg{target, done, total})
+	}
+}
+
+// Result adds one probe result to the list and histogram.
+func (f *Feed) Result(target string, r types.Result) {
+	if f != nil {
+		f.send(resultMsg{target, r})
+	}
+}
+
+// Finished marks target done, failed with err, or cancelled.
+func (f *Feed) Finished(target string, err error, cancelled bool) {
+	if f != nil {
+		f.send(finishedMsg{target, err, cancelled})
+	}
+}
+
+// Run shows the view on out until the user quits. scan runs in the background
+// with a Feed; when it returns the view shows the scan as complete and waits
+// for the user. Quitting early calls stop, which should cancel every target.
+// Run returns once the view closes, possibly before scan does.
+func Run(out io.Writer, targets []string, stop func(), scan func(*Feed)) error {
+	p := tea.NewProgram(New(targets, stop), tea.WithAltScreen(), tea.WithOutput(out))
+	go func() {
+		scan(&Feed{send: p.Send})
+		p.Send(allDoneMsg{})
+	}()
+	_, err := p.Run()
+	return err
+}
+
+type target struct {
+	name        string
+	state       string
+	done, total int
+	err         error
+	cancel      context.CancelFunc
+}
+
+type row struct {
+	target string
+	result types.Result
+	text   string // what the filter matches against
+}
+
+// Model is the bubbletea model for the scan view.
+type Model struct {
+	targets []*target
+	byName  map[string]*target
+	rows    []row
+	hist    map[string]int
+
+	selected  int
+	filter    string
+	filtering bool
+	offset    int  // first visible row of the filtered list
+	follow    bool // keep the newest rows in view
+	complete  bool
+	stop      func()
+
+	width, height int
+}
+
+// New builds the view for targets, in scan order.
+func New(targets []string, stop func()) *Model {
+	m := &Model{byName: map[string]*target{}, hist: map[string]int{}, follow: true, stop: stop, width: 80, height: 24}
+	for _, name := range targets {
+		t := &target{name: name, state: stateQueued}
+		m.targets = append(m.targets, t)
+		m.byName[name] = t
+	}
+	return m
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		if msg.Width > 0 && msg.Height > 0 { // some terminals report 0x0
+			m.width, m.height = msg.Width, msg.Height
+		}
+	case tea.KeyMsg:
+		return m, m.key(msg)
+	case startedMsg:
+		if t := m.byName[msg.target]; t != nil {
+			t.state, t.cancel = stateRunning, msg.cancel
+		}
+	case progressMsg:
+		if t := m.byName[msg.target]; t != nil {
+			t.done, t.total = msg.done, msg.total
+		}
+	case resultMsg:
+		m.rows = append(m.rows, row{msg.target, msg.result, rowText(msg.result)})
+		m.hist[statusKey(msg.result)]++
+	case finishedMsg:
+		if t := m.byName[msg.target]; t != nil {
+			switch {
+			case msg.cancelled:
+				t.state = stateCancelled
+			case msg.err != nil:
+				t.state, t.err = stateFailed, msg.err
+			default:
+				t.state = stateDone
+			}
+		}
+	case allDoneMsg:
+		m.complete = true
+	}
+	return m, nil
+}
+
+func (m *Model) key(k tea.KeyMsg) tea.Cmd {
+	if k.Type == tea.KeyRunes && len(k.Runes) > 1 && !m.filtering {
+		// Fast typing arrives as one message; run each key in turn.
+		var cmd tea.Cmd
+		for i, r := range k.Runes {
+			if m.filtering {
+				return m.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: k.Runes[i:]})
+			}
+			if c := m.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}); c != nil {
+				cmd = c
+			}
+		}
+		return cmd
+	}
+	if m.filtering {
+		switch k.Type {
+		case tea.KeyEnter:
+			m.filtering = false
+		case tea.KeyEsc:
+			m.filtering, m.filter = false, ""
+		case tea.KeyBackspace:
+			if r := []rune(m.filter); len(r) > 0 {
+				m.filter = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.filter += string(k.Runes)
+		case tea.KeyCtrlC:
+			return m.quit()
+		}
+		m.offset, m.follow = 0, true
+		return nil
+	}
+
+	switch k.String() {
+	case "q", "ctrl+c":
+		return m.quit()
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.selected < len(m.targets)-1 {
+			m.selected++
+		}
+	case "c", "x":
+		if len(m.targets) > 0 {
+			t := m.targets[m.selected]
+			if t.state == stateRunning && t.cancel != nil {
+				t.cancel()
+				t.state = stateCancelling
+			}
+		}
+	case "/":
+		m.filtering = true
+	case "esc":
+		m.filter = ""
+	case "pgup", "ctrl+u":
+		m.scroll(-m.listHeight())
+	case "pgdown", "ctrl+d":
+		m.scroll(m.listHeight())
+	case "home", "g":
+		m.offset, m.follow = 0, false
+	case "end", "G":
+		m.follow = true
+	}
+	return nil
+}
+
+func (m *Model) quit() tea.Cmd {
+	if !m.complete && m.stop != nil {
+		m.stop()
+	}
+	return tea.Quit
+}
+
+func (m *Model) scroll(by int) {
+	n := len(m.visible())
+	m.offset = clamp(m.offset+by, 0, max(n-m.listHeight(), 0))
+	m.follow = m.offset == max(n-m.listHeight(), 0)
+}
+
+// visible is the rows matching the filter: a case-insensitive substring of
+// the URL, status, class or target.
+func (m *Model) visible() []row {
+	if m.filter == "" {
+		return m.rows
+	}
+	f := strings.ToLower(m.filter)
+	var out []row
+	for _, r := range m.rows {
+		if strings.Contains(r.text, f) || strings.Contains(strings.ToLower(r.target), f) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// targetHeight is how many target lines fit: at most a third of the screen.
+func (m *Model) targetHeight() int {
+	return clamp(len(m.targets), 1, max(m.height/3, 1))
+}
+
+// listHeight is what's left for results after the header, targets,
+// histogram, rules and help line.
+func (m *Model) listHeight() int {
+	return max(m.height-m.targetHeight()-6, 1)
+}
+
+func (m *Model) View() string {
+	var b strings.Builder
+	b.WriteString(m.header() + "\n")
+
+	// Keep the selected target in view.
+	th := m.targetHeight()
+	first := clamp(m.selected-th+1, 0, max(len(m.targets)-th, 0))
+	for i := first; i < first+th && i < len(m.targets); i++ {
+		b.WriteString(m.targetLine(i) + "\n")
+	}
+
+	b.WriteString(m.histogram() + "\n")
+	b.WriteString(strings.Repeat("─", m.width) + "\n")
+
+	rows := m.visible()
+	lh := m.listHeight()
+	if m.follow {
+		m.offset = max(len(rows)-lh, 0)
+	}
+	m.offset = clamp(m.offset, 0, max(len(rows)-lh, 0))
+	for i := m.offset; i < m.offset+lh; i++ {
+		if i < len(rows) {
+			b.WriteString(m.resultLine(rows[i]))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Repeat("─", m.width) + "\n")
+	b.WriteString(m.footer(len(rows)))
+	return b.String()
+}
+
+func (m *Model) header() string {
+	var running, finished int
+	for _, t := range m.targets {
+		switch t.state {
+		case stateRunning, stateCancelling:
+			running++
+		case stateDone, stateFailed, stateCancelled:
+			finished++
+		}
+	}
+	h := fmt.Sprintf("parsero: %d targets, %d running, %d finished", len(m.targets), running, finished)
+	if m.complete {
+		h += ": scan complete, press q to exit"
+	}
+	return truncate(h, m.width)
+}
+
+const barWidth = 24
+
+func (m *Model) targetLine(i int) string {
+	t := m.targets[i]
+	cursor := "  "
+	if i == m.selected {
+		cursor = "▸ "
+	}
+
+	filled := 0
+	if t.total > 0 {
+		filled = barWidth * t.done / t.total
+	} else if t.state == stateDone {
+		filled = barWidth
+	}
+	bar := "[" + strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled) + "]"
+	count := fmt.Sprintf("%5d/%-5d", t.done, t.total)
+
+	state := t.state
+	if t.err != nil {
+		state += ": " + t.err.Error()
+	}
+	nameWidth := clamp(m.width-len(cursor)-barWidth-2-len(count)-4-len(state), 10, 40)
+	name := fmt.Sprintf("%-*s", nameWidth, truncate(t.name, nameWidth))
+
+	color := ""
+	switch t.state {
+	case stateDone:
+		color = colors.OKGREEN
+	case stateFailed:
+		color = colors.FAIL
+	case stateCancelling, stateCancelled:
+		color = colors.YELLOW
+	}
+	return cursor + name + " " + bar + " " + count + "  " + color + state + colors.ENDC
+}
+
+func (m *Model) histogram() string {
+	if len(m.hist) == 0 {
+		return "Status: waiting for results"
+	}
+	keys := make([]string, 0, len(m.hist))
+	for k := range m.hist {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // "error" sorts after the numeric codes
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, statusColor(k)+k+colors.ENDC+" ×"+strconv.Itoa(m.hist[k]))
+	}
+	return "Status: " + strings.Join(parts, "  ")
+}
+
+func (m *Model) resultLine(r row) string {
+	res := r.result
+	key := statusKey(res)
+	text := res.URL
+	if res.Class != "" {
+		text += " (" + res.Class + ")"
+	}
+	if res.Error != nil {
+		text += " " + res.Error.Error()
+	}
+	return statusColor(key) + fmt.Sprintf("%-5s", key) + colors.ENDC + " " + truncate(text, m.width-6)
+}
+
+func (m *Model) footer(shown int) string {
+	if m.filtering {
+		return truncate("Filter: "+m.filter+"▏ (enter to keep, esc to clear)", m.width)
+	}
+	status := fmt.Sprintf("%d results", len(m.rows))
+	if m.filter != "" {
+		status = fmt.Sprintf("%d of %d results match %q", shown, len(m.rows), m.filter)
+	}
+	return truncate(status+" · ↑/↓ target · c cancel target · / filter · PgUp/PgDn scroll · q quit", m.width)
+}
+
+func statusKey(r types.Result) string {
+	if r.Error != nil {
+		return "error"
+	}
+	return strconv.Itoa(r.StatusCode)
+}
+
+func statusColor(key string) string {
+	switch {
+	case key == "error":
+		return colors.YELLOW
+	case strings.HasPrefix(key, "2"):
+		return colors.OKGREEN
+	}
+	return colors.FAIL
+}
+
+func rowText(r types.Result) string {
+	return strings.ToLower(r.URL + " " + statusKey(r) + " " + r.Class)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 {
+		return ""
+	}
+	if len(r) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
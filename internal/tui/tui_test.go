@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+func send(m *Model, msgs ...tea.Msg) {
+	for _, msg := range msgs {
+		m.Update(msg)
+	}
+}
+
+func keys(m *Model, s string) {
+	for _, r := range s {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestViewProgressAndHistogram(t *testing.T) {
+	m := New([]string{"a.test", "b.test"}, nil)
+	send(m,
+		tea.WindowSizeMsg{Width: 100, Height: 30},
+		startedMsg{target: "a.test"},
+		progressMsg{"a.test", 2, 4},
+		resultMsg{"a.test", types.Result{URL: "http://a.test/admin/", StatusCode: 403, Class: "forbidden"}},
+		resultMsg{"a.test", types.Result{URL: "http://a.test/open/", StatusCode: 200, Class: "open"}},
+		resultMsg{"a.test", types.Result{URL: "http://a.test/slow/", Error: errors.New("timeout")}},
+		resultMsg{"a.test", types.Result{URL: "http://a.test/x/", StatusCode: 403}},
+	)
+	view := m.View()
+	for _, want := range []string{
+		"a.test", "running", "2/4", "b.test", "queued",
+		"200\x1b[0m ×1", "403\x1b[0m ×2", "error\x1b[0m ×1",
+		"http://a.test/open/ (open)", "timeout",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Count(view, "█") != barWidth/2 {
+		t.Errorf("half-done bar should fill %d cells:\n%s", barWidth/2, view)
+	}
+
+	send(m, finishedMsg{target: "a.test"}, finishedMsg{target: "b.test", err: errors.New("no robots.txt")}, allDoneMsg{})
+	view = m.View()
+	for _, want := range []string{"done", "failed: no robots.txt", "scan complete"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	m := New([]string{"a.test"}, nil)
+	send(m,
+		resultMsg{"a.test", types.Result{URL: "http://a.test/admin/", StatusCode: 403}},
+		resultMsg{"a.test", types.Result{URL: "http://a.test/open/", StatusCode: 200, Class: "open"}},
+	)
+	keys(m, "/adm")
+	if got := m.visible(); len(got) != 1 || !strings.HasSuffix(got[0].result.URL, "/admin/") {
+		t.Fatalf("filter 'adm' = %+v", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	keys(m, "200")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.visible(); len(got) != 1 || got[0].result.StatusCode != 200 {
+		t.Fatalf("filter '200' = %+v", got)
+	}
+	if !strings.Contains(m.View(), `1 of 2 results match "200"`) {
+		t.Errorf("footer doesn't show the filter:\n%s", m.View())
+	}
+
+	// Keys arriving in one message are split, so "/x" starts a filter.
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/open")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filter != "open" {
+		t.Fatalf("batched keys gave filter %q, want %q", m.filter, "open")
+	}
+	m.filter = "200"
+
+	// Keys typed while not filtering are commands, not filter text.
+	keys(m, "j")
+	if m.filter != "200" {
+		t.Errorf("filter changed to %q", m.filter)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.visible()) != 2 {
+		t.Error("esc should clear the filter")
+	}
+}
+
+func TestCancelSelectedTarget(t *testing.T) {
+	var cancelled []string
+	m := New([]string{"a.test", "b.test"}, nil)
+	send(m,
+		startedMsg{"a.test", func() { cancelled = append(cancelled, "a.test") }},
+		startedMsg{"b.test", func() { cancelled = append(cancelled, "b.test") }},
+	)
+	keys(m, "jc")
+	if len(cancelled) != 1 || cancelled[0] != "b.test" {
+		t.Fatalf("cancelled %v, want [b.test]", cancelled)
+	}
+	keys(m, "c") // already cancelling
+	if len(cancelled) != 1 {
+		t.Errorf("target cancelled twice: %v", cancelled)
+	}
+	send(m, finishedMsg{target: "b.test", cancelled: true})
+	if !strings.Contains(m.View(), "cancelled") {
+		t.Errorf("view doesn't show the cancellation:\n%s", m.View())
+	}
+}
+
+func TestQuitStopsUnfinishedScan(t *testing.T) {
+	stopped := false
+	m := New([]string{"a.test"}, func() { stopped = true })
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if !stopped || cmd == nil {
+		t.Errorf("q should stop the scan and quit (stopped=%v)", stopped)
+	}
+
+	stopped = false
+	m = New([]string{"a.test"}, func() { stopped = true })
+	send(m, allDoneMsg{})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if stopped {
+		t.Error("quitting a finished scan shouldn't cancel anything")
+	}
+}
+
+func TestScrollFollowsNewest(t *testing.T) {
+	m := New([]string{"a.test"}, nil)
+	send(m, tea.WindowSizeMsg{Width: 80, Height: 12})
+	for i := 0; i < 50; i++ {
+		send(m, resultMsg{"a.test", types.Result{URL: "http://a.test/p" + strings.Repeat("x", i), StatusCode: 404}})
+	}
+	if view := m.View(); !strings.Contains(view, "p"+strings.Repeat("x", 49)) {
+		t.Errorf("newest result not in view:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.follow {
+		t.Error("scrolling up should stop following")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if !m.follow {
+		t.Error("G should follow again")
+	}
+}
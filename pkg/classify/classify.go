@@ -0,0 +1,192 @@
+// Package classify labels a probe response by what it means for exposure
+// rather than by raw status: an open page, an auth wall (with its scheme), a
+// login form served as 200, a plain 403, a WAF block page, or nothing there.
+package classify
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	Open      = "open"      // content is served without authentication
+	Auth      = "auth"      // HTTP auth challenge (401/407); see Label.Scheme
+	Login     = "login"     // a login form, usually served as 200 or via redirect
+	Forbidden = "forbidden" // 403 without a recognisable block page
+	Blocked   = "waf"       // WAF, bot-protection or rate-limit block page
+	NotFound  = "not_found" // 404/410
+	Other     = "other"     // anything else (5xx, unfollowed redirects, …)
+)
+
+// Response is the part of a probe the classifier needs. Body may be empty when
+// only a HEAD was issued; URL is set only when the request was redirected, to
+// the final URL, so a bounce to a sign-in page reads as a login wall.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	URL        string
+}
+
+// Label is the class of a response and, for Auth, its scheme.
+type Label struct {
+	Class  string
+	Scheme string // auth scheme for Auth, e.g. "Basic", "Bearer", "NTLM"
+}
+
+var (
+	passwordInput = regexp.MustCompile(`(?i)<input[^>]+type\s*=\s*["']?password`)
+	loginPath     = regexp.MustCompile(`(?i)/(login|signin|sign-in|sign_in|sso|auth|logon|wp-login\.php)\b`)
+)
+
+// Classify labels r. Block pages are checked first since they hide behind
+// every status code a WAF chooses to return, including 200.
+func Classify(r Response) Label {
+	if blockPage(r) {
+		return Label{Class: Blocked}
+	}
+	switch code := r.StatusCode; {
+	case code == http.StatusUnauthorized || code == http.StatusProxyAuthRequired:
+		return Label{Class: Auth, Scheme: authScheme(r.Header)}
+	case code == http.StatusForbidden:
+		return Label{Class: Forbidden}
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return Label{Class: NotFound}
+	case code >= 200 && code < 300:
+		if passwordInput.Match(r.Body) || loginPath.MatchString(r.URL) {
+			return Label{Class: Login}
+		}
+		return Label{Class: Open}
+	case code >= 300 && code < 400:
+		if loginPath.MatchString(r.Header.Get("Location")) {
+			return Label{Class: Login}
+		}
+	}
+	return Label{Class: Other}
+}
+
+// Effective returns class, or the label implied by status alone for results
+// recorded before classification existed.
+func Effective(class string, status int) string {
+	if class != "" {
+		return class
+	}
+	return Classify(Response{StatusCode: status, Header: http.Header{}}).Class
+}
+
+// Reachable reports whether the path serves content to an anonymous visitor.
+func Reachable(class string, status int) bool {
+	return Effective(class, status) == Open
+}
+
+func authScheme(h http.Header) string {
+	v := h.Get("WWW-Authenticate")
+	if v == "" {
+		v = h.Get("Proxy-Authenticate")
+	}
+	scheme, _, _ := strings.Cut(strings.TrimSpace(v), " ")
+	return scheme
+}
+
+// blockPage reports whether r is a WAF, bot-protection or rate-limit response.
+func blockPage(r Response) bool {
+	_, ok := BlockedBy(r)
+	return ok
+}
+
+// BlockedBy names the WAF or bot-protection product behind a block page or
+// challenge, or "rate-limit" for a bare 429. Strong markers (challenge scripts,
+// block headers) count on any status; headers a vendor adds to everything it
+// proxies, and generic wording, only on the statuses block pages are served
+// with.
+func BlockedBy(r Response) (string, bool) {
+	if r.StatusCode == http.StatusTooManyRequests {
+		return "rate-limit", true
+	}
+	for _, h := range blockHeaders {
+		if h.proxied && !blockStatus(r.StatusCode) {
+			continue
+		}
+		if v := r.Header.Get(h.header); v != "" && strings.Contains(strings.ToLower(v), h.value) {
+			return h.vendor, true
+		}
+	}
+	body := strings.ToLower(string(r.Body))
+	for _, m := range strongBlockMarkers {
+		if strings.Contains(body, m.marker) {
+			return m.vendor, true
+		}
+	}
+	if blockStatus(r.StatusCode) {
+		for _, m := range blockMarkers {
+			if containsAll(body, m.markers) {
+				return m.vendor, true
+			}
+		}
+	}
+	return "", false
+}
+
+// blockStatus reports whether block pages are served with code.
+func blockStatus(code int) bool {
+	switch code {
+	case http.StatusForbidden, http.StatusNotAcceptable, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// headerMarker is a header that gives a vendor's block away; proxied ones
+// are on every response the vendor passes through, blocked or not.
+type headerMarker struct {
+	header, value, vendor string
+	proxied               bool
+}
+
+type bodyMarker struct{ marker, vendor string }
+
+// blockHeaders match when the header is present and contains value.
+var blockHeaders = []headerMarker{
+	{"Cf-Mitigated", "", "cloudflare", false},
+	{"X-Amzn-Waf-Action", "", "aws-waf", false},
+	{"X-Datadome", "", "datadome", true},
+	{"X-Sucuri-Block", "", "sucuri", false},
+	{"X-Iinfo", "", "imperva", true},
+}
+
+var strongBlockMarkers = []bodyMarker{
+	{"/cdn-cgi/challenge-platform/", "cloudflare"},
+	{"cf-chl-", "cloudflare"},
+	{"_incapsula_resource", "imperva"},
+	{"incapsula incident id", "imperva"},
+	{"captcha-delivery.com", "datadome"},
+	{"px-captcha", "perimeterx"},
+	{"sucuri website firewall", "sucuri"},
+	{"the requested url was rejected. please consult with your administrator", "f5-asm"},
+	{"this error was generated by mod_security", "modsecurity"},
+}
+
+// blockMarkers match when the body contains all of markers. Wording that
+// stock server error pages share ("access denied", Apache's "you don't have
+// permission to access") only counts next to a vendor's own marker.
+var blockMarkers = []struct {
+	markers []string
+	vendor  string
+}{
+	{[]string{"attention required! | cloudflare"}, "cloudflare"},
+	{[]string{"just a moment..."}, "cloudflare"},
+	{[]string{"access denied", "reference&#32;&#35;"}, "akamai"},
+	{[]string{"request blocked", "cloudfront"}, "aws-waf"},
+	{[]string{"g-recaptcha"}, "captcha"},
+	{[]string{"h-captcha"}, "captcha"},
+}
+
+func containsAll(s string, subs []string) bool {
+	for _, sub := range subs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package classify
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	hdr := func(kv ...string) http.Header {
+		h := http.Header{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			h.Set(kv[i], kv[i+1])
+		}
+		return h
+	}
+	cases := []struct {
+		name       string
+		resp       Response
+		wantClass  string
+		wantScheme string
+	}{
+		{"open", Response{StatusCode: 200, Header: hdr(), Body: []byte("<h1>hi</h1>")}, Open, ""},
+		{"basic auth", Response{StatusCode: 401, Header: hdr("WWW-Authenticate", `Basic realm="admin"`)}, Auth, "Basic"},
+		{"bearer auth", Response{StatusCode: 401, Header: hdr("WWW-Authenticate", "Bearer")}, Auth, "Bearer"},
+		{"login form", Response{StatusCode: 200, Header: hdr(), Body: []byte(`<form><input type="password" name="p"></form>`)}, Login, ""},
+		{"redirected to login", Response{StatusCode: 200, Header: hdr(), URL: "https://x/users/sign_in?next=/admin"}, Login, ""},
+		{"author page", Response{StatusCode: 200, Header: hdr(), URL: "https://x/author/jane"}, Open, ""},
+		{"redirect to login", Response{StatusCode: 302, Header: hdr("Location", "/auth/?r=/admin")}, Login, ""},
+		{"forbidden", Response{StatusCode: 403, Header: hdr()}, Forbidden, ""},
+		{"cloudflare block", Response{StatusCode: 403, Header: hdr(), Body: []byte("<title>Attention Required! | Cloudflare</title>")}, Blocked, ""},
+		{"challenge header", Response{StatusCode: 200, Header: hdr("cf-mitigated", "challenge")}, Blocked, ""},
+		{"behind imperva", Response{StatusCode: 200, Header: hdr("X-Iinfo", "13-4567-0 NNNN CT(0 0 0) RT(1 0) q(0 0 0 -1) r(0 0) U6")}, Open, ""},
+		{"behind datadome", Response{StatusCode: 200, Header: hdr("X-Datadome", "protected")}, Open, ""},
+		{"imperva block", Response{StatusCode: 403, Header: hdr("X-Iinfo", "13-4567-0 NNNN")}, Blocked, ""},
+		{"rate limited", Response{StatusCode: 429, Header: hdr()}, Blocked, ""},
+		{"not found", Response{StatusCode: 404, Header: hdr()}, NotFound, ""},
+		{"server error", Response{StatusCode: 500, Header: hdr()}, Other, ""},
+	}
+	for _, c := range cases {
+		got := Classify(c.resp)
+		if got.Class != c.wantClass || got.Scheme != c.wantScheme {
+			t.Errorf("%s: got %+v, want {%s %s}", c.name, got, c.wantClass, c.wantScheme)
+		}
+	}
+}
+
+func TestEffectiveFallsBackToStatus(t *testing.T) {
+	if got := Effective("", 200); got != Open {
+		t.Errorf("Effective(\"\", 200) = %q, want %q", got, Open)
+	}
+	if got := Effective(Login, 200); got != Login {
+		t.Errorf("Effective(login, 200) = %q, want %q", got, Login)
+	}
+	if Reachable(Login, 200) {
+		t.Error("a login page must not count as reachable")
+	}
+}
+
+func TestBlockedBy(t *testing.T) {
+	cases := []struct {
+		resp Response
+		want string
+	}{
+		{Response{StatusCode: 200, Header: http.Header{}, Body: []byte(`<script src="/cdn-cgi/challenge-platform/h/b/orchestrate"></script>`)}, "cloudflare"},
+		{Response{StatusCode: 403, Header: http.Header{}, Body: []byte("Incapsula incident ID: 123")}, "imperva"},
+		{Response{StatusCode: 403, Header: http.Header{"X-Datadome": {"protected"}}}, "datadome"},
+		{Response{StatusCode: 503, Header: http.Header{}, Body: []byte("<title>Just a moment...</title>")}, "cloudflare"},
+		{Response{StatusCode: 429, Header: http.Header{}}, "rate-limit"},
+		{Response{StatusCode: 403, Header: http.Header{}, Body: []byte(akamaiDenied)}, "akamai"},
+	}
+	for _, c := range cases {
+		got, ok := BlockedBy(c.resp)
+		if !ok || got != c.want {
+			t.Errorf("BlockedBy(%d %q) = %q, %v; want %q", c.resp.StatusCode, c.resp.Body, got, ok, c.want)
+		}
+	}
+	// Generic wording on a 200 page is just content.
+	if _, ok := BlockedBy(Response{StatusCode: 200, Header: http.Header{}, Body: []byte("access denied to guests")}); ok {
+		t.Error("generic marker on a 200 should not count as a block page")
+	}
+	// Nor is a stock server 403 a WAF, however it words the refusal.
+	for _, body := range []string{apacheForbidden, "<h1>Access Denied</h1>"} {
+		resp := Response{StatusCode: 403, Header: http.Header{}, Body: []byte(body)}
+		if vendor, ok := BlockedBy(resp); ok {
+			t.Errorf("plain 403 %q taken for a %s block page", body, vendor)
+		}
+		if got := Classify(resp).Class; got != Forbidden {
+			t.Errorf("plain 403 classified %q, want %q", got, Forbidden)
+		}
+	}
+}
+
+const apacheForbidden = `<!DOCTYPE HTML PUBLIC "-//IETF//DTD HTML 2.0//EN">
+<html><head>
+<title>403 Forbidden</title>
+</head><body>
+<h1>Forbidden</h1>
+<p>You don't have permission to access this resource.</p>
+<hr>
+<address>Apache/2.4.58 (Ubuntu) Server at example.com Port 80</address>
+</body></html>`
+
+const akamaiDenied = `<HTML><HEAD>
+<TITLE>Access Denied</TITLE>
+</HEAD><BODY>
+<H1>Access Denied</H1>
+You don't have permission to access "http&#58;&#47;&#47;example&#46;com&#47;admin&#47;" on this server.<P>
+Reference&#32;&#35;18&#46;6f64d17&#46;1700000000&#46;1a2b3c
+</BODY>
+</HTML>`
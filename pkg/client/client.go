@@ -0,0 +1,168 @@
+// Package client is a Go SDK for the parserod REST API: scans, results,
+// reports, robots.txt linting and recurring monitors, with live progress over
+// the /events stream. Every method honours its context.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client talks to one parserod instance. It is safe for concurrent use.
+type Client struct {
+	base   string
+	http   *http.Client
+	header http.Header
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient,
+// e.g. for a proxy, custom TLS or timeouts. Event streams run until the scan
+// finishes, so hc shouldn't set a Timeout shorter than a scan.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithAPIKey sends "Authorization: Bearer key" with every request, for an
+// authenticating proxy in front of parserod.
+func WithAPIKey(key string) Option {
+	return WithHeader("Authorization", "Bearer "+key)
+}
+
+// WithHeader sends a header with every request, e.g. the identity header
+// parserod trusts from its proxy (X-Auth-Request-Email by default).
+func WithHeader(name, value string) Option {
+	return func(c *Client) { c.header.Add(name, value) }
+}
+
+// New returns a client for the server at baseURL, e.g.
+// "https://parsero.example.com".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("client: invalid base URL %q (want an http(s) URL)", baseURL)
+	}
+	c := &Client{
+		base:   strings.TrimSuffix(baseURL, "/"),
+		http:   http.DefaultClient,
+		header: http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Sentinel errors an *Error matches with errors.Is, by HTTP status.
+var (
+	ErrInvalid      = errors.New("invalid request")    // 400
+	ErrUnauthorized = errors.New("unauthorized")       // 401 or 403, from an auth proxy
+	ErrNotFound     = errors.New("not found")          // 404, or 410 for an expired artifact
+	ErrConflict     = errors.New("conflict")           // 409: e.g. the HAR of an unfinished scan
+	ErrThrottled    = errors.New("throttled")          // 429: rate limit, user cap or full queue
+	ErrUnavailable  = errors.New("server unavailable") // 502, 503
+)
+
+// Error is a non-2xx response. Message is the server's {"error": ...} text,
+// or the status line when the body isn't one.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Path, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrInvalid:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrThrottled:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// do sends a request and returns the response when it succeeded; the caller
+// closes the body.
+func (c *Client) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, rd)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	apiErr := &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Message: resp.Status}
+	var msg struct {
+		Error string `json:"error"`
+	}
+	if json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&msg) == nil && msg.Error != "" {
+		apiErr.Message = msg.Error
+	}
+	return nil, apiErr
+}
+
+// call is do for JSON endpoints: it decodes the response into out, if non-nil.
+func (c *Client) call(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+// Health reports whether the server and its database are up.
+func (c *Client) Health(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodGet, "/healthz", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
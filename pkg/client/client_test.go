@@ -0,0 +1,351 @@
+package client_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zvdy/parsero-go/internal/server/servertest"
+	"github.com/zvdy/parsero-go/internal/store"
+	"github.com/zvdy/parsero-go/pkg/client"
+	"github.com/zvdy/parsero-go/pkg/export"
+)
+
+// target is a public IP literal, so the server's SSRF check passes without DNS.
+const target = "93.184.215.14"
+
+func newClient(t *testing.T, user string) (*client.Client, *servertest.Backend) {
+	t.Helper()
+	srv, backend := servertest.New(t)
+	c, err := client.New(srv.URL, client.WithHeader(servertest.IdentityHeader, user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, backend
+}
+
+func TestScanLifecycle(t *testing.T) {
+	c, backend := newClient(t, "ci@example.com")
+	ctx := context.Background()
+
+	sc, err := c.CreateScan(ctx, client.ScanRequest{Target: "https://" + target + "/x", Only200: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Status != client.StatusQueued || sc.Target != target || !sc.Only200 {
+		t.Fatalf("created %+v", sc)
+	}
+	if q := backend.Queued(); len(q) != 1 || q[0] != sc.ID {
+		t.Fatalf("queued %v, want [%s]", q, sc.ID)
+	}
+
+	// Play the worker: report progress, then finish after the handler's
+	// first poll has seen it.
+	backend.Progress(sc.ID, 1, 2)
+	go func() {
+		time.Sleep(1500 * time.Millisecond)
+		backend.Complete(sc.ID, []store.ResultRow{
+			{URL: "http://" + target + "/jenkins/", StatusCode: 200, Status: "200 OK", Source: "robots", Class: "open", Fingerprints: []string{"jenkins"}},
+			{URL: "http://" + target + "/admin/", StatusCode: 403, Status: "403 Forbidden", Source: "robots", Class: "forbidden"},
+		})
+	}()
+	var mu sync.Mutex
+	var seen []client.Progress
+	done, err := c.Wait(ctx, sc.ID, func(p client.Progress) {
+		mu.Lock()
+		seen = append(seen, p)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != client.StatusDone || done.TotalPaths != 2 || done.Status200 != 1 {
+		t.Fatalf("finished %+v", done)
+	}
+	if len(seen) == 0 || seen[0] != (client.Progress{Status: client.StatusRunning, Done: 1, Total: 2}) {
+		t.Errorf("progress events %+v", seen)
+	}
+
+	results, err := c.Results(ctx, sc.ID)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Results = %v, %v", results, err)
+	}
+	results, err = c.Results(ctx, sc.ID, "jenkins")
+	if err != nil || len(results) != 1 || results[0].Class != "open" {
+		t.Fatalf("Results(jenkins) = %v, %v", results, err)
+	}
+
+	for name, open := range map[string]func() (io.ReadCloser, error){
+		"sarif": func() (io.ReadCloser, error) { return c.SARIF(ctx, sc.ID) },
+		"csv":   func() (io.ReadCloser, error) { return c.Report(ctx, sc.ID, "csv") },
+	} {
+		rc, err := open()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		if !strings.Contains(string(body), "/jenkins/") {
+			t.Errorf("%s report missing the reachable path:\n%s", name, body)
+		}
+	}
+
+	// Reports of an only200 scan list its 200s, as the CLI's do, and keep
+	// the Disallow list for verify and diff.
+	rc, err := c.Report(ctx, sc.ID, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rep export.ScanResult
+	err = json.NewDecoder(rc).Decode(&rep)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Results) != 1 || rep.TotalPaths != 1 || !strings.HasSuffix(rep.Results[0].URL, "/jenkins/") {
+		t.Errorf("only200 report results = %+v", rep.Results)
+	}
+	if strings.Join(rep.Disallow, ",") != "jenkins/,admin/" {
+		t.Errorf("report disallow = %v", rep.Disallow)
+	}
+	if _, err := c.Report(ctx, sc.ID, "pdf"); !errors.Is(err, client.ErrInvalid) {
+		t.Errorf("Report(pdf) error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestWaitFailed(t *testing.T) {
+	c, backend := newClient(t, "ci@example.com")
+	ctx := context.Background()
+	sc, err := c.CreateScan(ctx, client.ScanRequest{Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Fail(sc.ID, "robots.txt unreachable: connection refused")
+
+	done, err := c.Wait(ctx, sc.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != client.StatusFailed || done.ErrorMessage != "robots.txt unreachable: connection refused" || done.Target != target {
+		t.Errorf("Wait = %+v", done)
+	}
+}
+
+// A target without robots.txt is a finished scan with nothing probed; only
+// RobotsStatus tells it from one listing nothing.
+func TestWaitNoRobots(t *testing.T) {
+	c, backend := newClient(t, "ci@example.com")
+	ctx := context.Background()
+	sc, err := c.CreateScan(ctx, client.ScanRequest{Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.NoRobots(sc.ID)
+
+	done, err := c.Wait(ctx, sc.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != client.StatusDone || done.RobotsStatus != 404 || done.TotalPaths != 0 {
+		t.Errorf("Wait = %+v", done)
+	}
+}
+
+func TestWaitHonoursContext(t *testing.T) {
+	c, _ := newClient(t, "ci@example.com")
+	sc, err := c.CreateScan(context.Background(), client.ScanRequest{Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := c.Wait(ctx, sc.ID, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestListScansPages(t *testing.T) {
+	srv, _ := servertest.New(t)
+	ctx := context.Background()
+	alice, _ := client.New(srv.URL, client.WithHeader(servertest.IdentityHeader, "alice"))
+	bob, _ := client.New(srv.URL, client.WithHeader(servertest.IdentityHeader, "bob"))
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		sc, err := alice.CreateScan(ctx, client.ScanRequest{Target: target, Only200: i%2 == 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append([]string{sc.ID}, ids...) // newest first
+	}
+
+	var got []string
+	opts := client.ListOptions{Limit: 2}
+	pages := 0
+	for {
+		page, err := alice.ListScans(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, sc := range page.Scans {
+			got = append(got, sc.ID)
+		}
+		if page.Next == "" {
+			break
+		}
+		opts.Before = page.Next
+	}
+	if pages != 3 || strings.Join(got, ",") != strings.Join(ids, ",") {
+		t.Errorf("paged %d times through %v, want 3 pages of %v", pages, got, ids)
+	}
+
+	all, err := alice.AllScans(ctx)
+	if err != nil || len(all) != 5 {
+		t.Errorf("AllScans = %d scans, %v", len(all), err)
+	}
+	if page, err := bob.ListScans(ctx, client.ListOptions{}); err != nil || len(page.Scans) != 0 {
+		t.Errorf("bob sees %d of alice's scans (%v)", len(page.Scans), err)
+	}
+	if _, err := bob.ListScans(ctx, client.ListOptions{Before: ids[0]}); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("bob paging from alice's scan: %v, want ErrNotFound", err)
+	}
+	if _, err := alice.ListScans(ctx, client.ListOptions{Limit: 500}); !errors.Is(err, client.ErrInvalid) {
+		t.Errorf("Limit 500: %v, want ErrInvalid", err)
+	}
+}
+
+func TestReportRenderError(t *testing.T) {
+	export.Register(export.Format{Name: "broken", Write: func(w io.Writer, _ export.ScanResult) error {
+		io.WriteString(w, "partial")
+		return errors.New("render failed")
+	}})
+	c, backend := newClient(t, "ci@example.com")
+	ctx := context.Background()
+	sc, err := c.CreateScan(ctx, client.ScanRequest{Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Complete(sc.ID, nil)
+
+	var apiErr *client.Error
+	if _, err := c.Report(ctx, sc.ID, "broken"); !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Errorf("Report(broken) error = %v, want a 500", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c, _ := newClient(t, "ci@example.com")
+	ctx := context.Background()
+
+	_, err := c.GetScan(ctx, "missing")
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || apiErr.Message != "scan not found" {
+		t.Fatalf("GetScan(missing) error = %#v", err)
+	}
+	if !errors.Is(err, client.ErrNotFound) || errors.Is(err, client.ErrInvalid) {
+		t.Errorf("%v should match only ErrNotFound", err)
+	}
+
+	for _, bad := range []string{"localhost", "10.0.0.1", ""} {
+		if _, err := c.CreateScan(ctx, client.ScanRequest{Target: bad}); !errors.Is(err, client.ErrInvalid) {
+			t.Errorf("CreateScan(%q) error = %v, want ErrInvalid", bad, err)
+		}
+	}
+	if _, err := c.Lint(ctx, "localhost"); !errors.Is(err, client.ErrInvalid) {
+		t.Errorf("Lint(localhost) error = %v, want ErrInvalid", err)
+	}
+	if err := c.Health(ctx); err != nil {
+		t.Errorf("Health: %v", err)
+	}
+	if _, err := client.New("parsero.example.com"); err == nil {
+		t.Error("New accepted a base URL without a scheme")
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	c, _ := newClient(t, "ci@example.com")
+	ctx := context.Background()
+
+	if _, err := c.CreateSchedule(ctx, client.ScheduleRequest{Target: target, Cron: "every day"}); !errors.Is(err, client.ErrInvalid) {
+		t.Errorf("bad cron: %v, want ErrInvalid", err)
+	}
+	sc, err := c.CreateSchedule(ctx, client.ScheduleRequest{
+		Target: target, Cron: "@daily", NotifyOnChange: true,
+		AlertFingerprints: []string{"jenkins"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc.Enabled || sc.Cron != "@daily" || len(sc.AlertFingerprints) != 1 {
+		t.Fatalf("created %+v", sc)
+	}
+
+	if err := c.DisableSchedule(ctx, sc.ID); err != nil {
+		t.Fatal(err)
+	}
+	list, err := c.ListSchedules(ctx)
+	if err != nil || len(list) != 1 || list[0].Enabled {
+		t.Fatalf("after disable: %+v, %v", list, err)
+	}
+	if err := c.EnableSchedule(ctx, sc.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteSchedule(ctx, sc.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteSchedule(ctx, sc.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("second delete: %v, want ErrNotFound", err)
+	}
+}
+
+func TestHAR(t *testing.T) {
+	c, backend := newClient(t, "ci@example.com")
+	ctx := context.Background()
+
+	plain, err := c.CreateScan(ctx, client.ScanRequest{Target: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := c.CreateScan(ctx, client.ScanRequest{Target: target, HAR: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc.HAR || sc.ID == plain.ID {
+		t.Fatalf("recorded scan %+v reused %s", sc, plain.ID)
+	}
+	if _, err := c.HAR(ctx, sc.ID); !errors.Is(err, client.ErrConflict) {
+		t.Errorf("HAR of a queued scan: %v, want ErrConflict", err)
+	}
+	backend.Complete(sc.ID, nil)
+	if _, err := c.HAR(ctx, sc.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("HAR without an artifact: %v, want ErrNotFound", err)
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(`{"log":{"version":"1.2"}}`))
+	zw.Close()
+	backend.Artifact(sc.ID, store.ArtifactHAR, gz.Bytes())
+	rc, err := c.HAR(ctx, sc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != `{"log":{"version":"1.2"}}` {
+		t.Errorf("HAR body %q", body)
+	}
+
+	backend.Complete(plain.ID, nil)
+	if _, err := c.HAR(ctx, plain.ID); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("HAR of an unrecorded scan: %v, want ErrNotFound", err)
+	}
+}
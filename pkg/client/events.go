@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Progress is a running scan's progress event.
+type Progress struct {
+	Status string `json:"status"`
+	Done   int    `json:"done"`
+	Total  int    `json:"total"`
+}
+
+// Events follows one connection to a scan's event stream, calling fn (if
+// non-nil) for each progress event. It returns the scan once a done or failed
+// event arrives; a failed scan carries only its ErrorMessage. A stream that
+// ends first yields io.ErrUnexpectedEOF.
+func (c *Client) Events(ctx context.Context, id string, fn func(Progress)) (Scan, error) {
+	path := scanPath(id) + "/events"
+	resp, err := c.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return Scan{}, err
+	}
+	defer resp.Body.Close()
+
+	var event string
+	var data []string
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() {
+		line := lines.Text()
+		if line != "" {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+			continue
+		}
+
+		// A blank line dispatches the event.
+		payload := []byte(strings.Join(data, "\n"))
+		name := event
+		event, data = "", nil
+		switch name {
+		case "progress":
+			var p Progress
+			if json.Unmarshal(payload, &p) == nil && fn != nil {
+				fn(p)
+			}
+		case "done":
+			var sc Scan
+			if err := json.Unmarshal(payload, &sc); err != nil {
+				return Scan{}, fmt.Errorf("GET %s: decoding done event: %w", path, err)
+			}
+			return sc, nil
+		case "failed":
+			var f struct {
+				Error string `json:"error"`
+			}
+			json.Unmarshal(payload, &f)
+			return Scan{ID: id, Status: StatusFailed, ErrorMessage: f.Error}, nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Scan{}, err
+	}
+	if err := lines.Err(); err != nil {
+		return Scan{}, err
+	}
+	return Scan{}, io.ErrUnexpectedEOF
+}
+
+// Wait blocks until the scan finishes, following its event stream and
+// reconnecting if the stream drops (a proxy idle timeout, a server restart).
+// A failed scan is not an error: check the returned Scan's Status.
+func (c *Client) Wait(ctx context.Context, id string, fn func(Progress)) (Scan, error) {
+	for {
+		sc, err := c.Events(ctx, id, fn)
+		if err == nil {
+			if sc.Status == StatusFailed {
+				// The failed event carries only the message; fetch the rest.
+				if full, err := c.GetScan(ctx, id); err == nil && full.Finished() {
+					return full, nil
+				}
+			}
+			return sc, nil
+		}
+		if err != io.ErrUnexpectedEOF {
+			return Scan{}, err
+		}
+		if sc, err = c.GetScan(ctx, id); err != nil || sc.Finished() {
+			return sc, err
+		}
+		select {
+		case <-ctx.Done():
+			return Scan{}, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
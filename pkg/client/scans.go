@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// Scan statuses.
+const (
+	StatusQueued  = "queued"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// ScanRequest submits a scan. The server answers with a recent identical scan
+// when it has one (Scan.Cached), unless HAR asks for the scan's traffic to be
+// recorded (see Client.HAR).
+type ScanRequest struct {
+	Target     string `json:"target"`
+	Only200    bool   `json:"only200"`
+	SearchBing bool   `json:"search_bing"`
+	HAR        bool   `json:"har,omitempty"`
+}
+
+type Scan struct {
+	ID              string    `json:"id"`
+	Target          string    `json:"target"`
+	Status          string    `json:"status"`
+	Cached          bool      `json:"cached,omitempty"`
+	Only200         bool      `json:"only200"`
+	SearchBing      bool      `json:"search_bing"`
+	HAR             bool      `json:"har,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	TotalPaths      int       `json:"total_paths"`
+	Status200       int       `json:"status_200"`
+	OtherStatus     int       `json:"other_status"`
+	Errors          int       `json:"errors"`
+	ErrorMessage    string    `json:"error_message,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	Degraded        bool      `json:"degraded,omitempty"`
+	DegradedReason  string    `json:"degraded_reason,omitempty"`
+	// RobotsStatus is the target's robots.txt HTTP status; 404 means it has
+	// none. 0 when the server reused a cached robots.txt.
+	RobotsStatus int `json:"robots_status,omitempty"`
+
+	Concurrency *types.ConcurrencyProfile `json:"concurrency,omitempty"`
+}
+
+// Finished reports whether the scan is done or failed.
+func (s Scan) Finished() bool { return s.Status == StatusDone || s.Status == StatusFailed }
+
+// Result is one probed path.
+type Result struct {
+	URL          string   `json:"url"`
+	StatusCode   int      `json:"status_code,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Source       string   `json:"source"`
+	Fingerprints []string `json:"fingerprints,omitempty"`
+	Class        string   `json:"class,omitempty"`
+	AuthScheme   string   `json:"auth_scheme,omitempty"`
+}
+
+// ToTypes converts results to the scanner's result type, the one pkg/export
+// renders.
+func ToTypes(rows []Result) []types.Result {
+	results := make([]types.Result, 0, len(rows))
+	for _, rw := range rows {
+		res := types.Result{
+			URL: rw.URL, StatusCode: rw.StatusCode, Status: rw.Status,
+			Source: rw.Source, Fingerprints: rw.Fingerprints,
+			Class: rw.Class, AuthScheme: rw.AuthScheme,
+		}
+		if rw.Error != "" {
+			res.Error = errors.New(rw.Error)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// ListOptions pages through a listing. Before is the Next cursor of the
+// previous page; Limit defaults to 50 and may be at most 200.
+type ListOptions struct {
+	Limit  int
+	Before string
+}
+
+// ScanPage is one page of scans, newest first. Next is empty on the last page.
+type ScanPage struct {
+	Scans []Scan
+	Next  string
+}
+
+func scanPath(id string) string { return "/api/scans/" + url.PathEscape(id) }
+
+func (c *Client) CreateScan(ctx context.Context, req ScanRequest) (Scan, error) {
+	var sc Scan
+	err := c.call(ctx, http.MethodPost, "/api/scans", req, &sc)
+	return sc, err
+}
+
+func (c *Client) GetScan(ctx context.Context, id string) (Scan, error) {
+	var sc Scan
+	err := c.call(ctx, http.MethodGet, scanPath(id), nil, &sc)
+	return sc, err
+}
+
+// ListScans returns one page of the caller's scans.
+func (c *Client) ListScans(ctx context.Context, opts ListOptions) (ScanPage, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	q := url.Values{"limit": {strconv.Itoa(limit)}}
+	if opts.Before != "" {
+		q.Set("before", opts.Before)
+	}
+	var page ScanPage
+	if err := c.call(ctx, http.MethodGet, "/api/scans?"+q.Encode(), nil, &page.Scans); err != nil {
+		return ScanPage{}, err
+	}
+	if len(page.Scans) == limit {
+		page.Next = page.Scans[limit-1].ID
+	}
+	return page, nil
+}
+
+// AllScans walks every page of the caller's scans, newest first.
+func (c *Client) AllScans(ctx context.Context) ([]Scan, error) {
+	var all []Scan
+	opts := ListOptions{Limit: 200}
+	for {
+		page, err := c.ListScans(ctx, opts)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, page.Scans...)
+		if page.Next == "" {
+			return all, nil
+		}
+		opts.Before = page.Next
+	}
+}
+
+// Results returns a scan's probed paths, keeping only those fingerprinted as
+// one of tech when any are given.
+func (c *Client) Results(ctx context.Context, id string, tech ...string) ([]Result, error) {
+	path := scanPath(id) + "/results"
+	if len(tech) > 0 {
+		path += "?fingerprint=" + url.QueryEscape(strings.Join(tech, ","))
+	}
+	var out []Result
+	err := c.call(ctx, http.MethodGet, path, nil, &out)
+	return out, err
+}
+
+// SARIF streams a scan's results as a SARIF 2.1.0 log; the caller closes it.
+func (c *Client) SARIF(ctx context.Context, id string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, scanPath(id)+"/sarif", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Report streams a scan rendered in a report format (json, csv, markdown,
+// html or junit); the caller closes it.
+func (c *Client) Report(ctx context.Context, id, format string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, scanPath(id)+"/report?format="+url.QueryEscape(format), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// HAR streams the HTTP Archive of a scan created with ScanRequest.HAR; the
+// caller closes it. It fails with ErrConflict while the scan runs, and with
+// ErrNotFound once the server's retention has expired the recording.
+func (c *Client) HAR(ctx context.Context, id string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, scanPath(id)+"/har", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// LintFinding is one robots.txt lint finding.
+type LintFinding struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+type LintReport struct {
+	Target   string        `json:"target"`
+	Findings []LintFinding `json:"findings"`
+}
+
+// Lint fetches and lints target's robots.txt on the server. A target with no
+// robots.txt fails with ErrNotFound.
+func (c *Client) Lint(ctx context.Context, target string) (LintReport, error) {
+	var rep LintReport
+	err := c.call(ctx, http.MethodGet, "/api/lint?target="+url.QueryEscape(target), nil, &rep)
+	return rep, err
+}
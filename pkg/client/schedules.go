@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ScheduleRequest creates a monitor that rescans Target on the Cron schedule
+// (5 fields or an @descriptor) and notifies NotifyWebhook after each run.
+type ScheduleRequest struct {
+	Target         string `json:"target"`
+	Cron           string `json:"cron"`
+	Only200        bool   `json:"only200"`
+	SearchBing     bool   `json:"search_bing"`
+	NotifyWebhook  string `json:"notify_webhook,omitempty"`
+	NotifyOnChange bool   `json:"notify_on_change"`
+	// AlertFingerprints restricts alerts to paths fingerprinted as one of these.
+	AlertFingerprints []string `json:"alert_fingerprints,omitempty"`
+}
+
+type Schedule struct {
+	ID             string     `json:"id"`
+	Target         string     `json:"target"`
+	Cron           string     `json:"cron"`
+	Enabled        bool       `json:"enabled"`
+	Only200        bool       `json:"only200"`
+	SearchBing     bool       `json:"search_bing"`
+	NotifyWebhook  string     `json:"notify_webhook,omitempty"`
+	NotifyOnChange bool       `json:"notify_on_change"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+
+	AlertFingerprints []string `json:"alert_fingerprints,omitempty"`
+}
+
+func schedulePath(id string) string { return "/api/schedules/" + url.PathEscape(id) }
+
+func (c *Client) CreateSchedule(ctx context.Context, req ScheduleRequest) (Schedule, error) {
+	var sc Schedule
+	err := c.call(ctx, http.MethodPost, "/api/schedules", req, &sc)
+	return sc, err
+}
+
+// ListSchedules returns all of the caller's monitors, newest first.
+func (c *Client) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	var out []Schedule
+	err := c.call(ctx, http.MethodGet, "/api/schedules", nil, &out)
+	return out, err
+}
+
+func (c *Client) DeleteSchedule(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, schedulePath(id), nil, nil)
+}
+
+func (c *Client) EnableSchedule(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, schedulePath(id)+"/enable", nil, nil)
+}
+
+func (c *Client) DisableSchedule(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, schedulePath(id)+"/disable", nil, nil)
+}
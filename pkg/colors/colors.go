@@ -1,8 +1,15 @@
 package colors
 
-const (
+// ANSI escapes for terminal output. They are variables so Disable can blank
+// them for pipes, log files and NO_COLOR users.
+var (
 	OKGREEN = "\033[92m"
 	FAIL    = "\033[91m"
 	ENDC    = "\033[0m"
 	YELLOW  = "\033[33m"
 )
+
+// Disable turns every color into the empty string.
+func Disable() {
+	OKGREEN, FAIL, ENDC, YELLOW = "", "", "", ""
+}
@@ -0,0 +1,27 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func writeCSV(w io.Writer, r ScanResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"url", "status_code", "status", "class", "auth_scheme", "source", "fingerprints", "error"})
+	for _, res := range r.Results {
+		cw.Write([]string{
+			res.URL,
+			strconv.Itoa(res.StatusCode),
+			res.Status,
+			res.Class,
+			res.AuthScheme,
+			res.Source,
+			strings.Join(res.Fingerprints, ";"),
+			errText(res),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
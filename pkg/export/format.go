@@ -0,0 +1,107 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/sensitive"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// Format renders a ScanResult in one report format.
+type Format struct {
+	Name        string
+	Extension   string
+	ContentType string
+	Write       func(w io.Writer, r ScanResult) error
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Format{}
+)
+
+// Register adds f to the registry, replacing any format with the same name.
+func Register(f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[strings.ToLower(f.Name)] = f
+}
+
+// Lookup returns the format registered under name (case-insensitive).
+func Lookup(name string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f, ok := formats[strings.ToLower(name)]
+	return f, ok
+}
+
+// Formats lists the registered format names, sorted.
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write renders r in the named format.
+func Write(w io.Writer, format string, r ScanResult) error {
+	f, ok := Lookup(format)
+	if !ok {
+		return fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(Formats(), ", "))
+	}
+	return f.Write(w, r)
+}
+
+// WriteFile renders r in the named format to filePath.
+func WriteFile(filePath, format string, r ScanResult) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+	if err := Write(f, format, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	Register(Format{Name: "json", Extension: ".json", ContentType: "application/json", Write: writeJSON})
+	Register(Format{Name: "csv", Extension: ".csv", ContentType: "text/csv; charset=utf-8", Write: writeCSV})
+	Register(Format{Name: "markdown", Extension: ".md", ContentType: "text/markdown; charset=utf-8", Write: writeMarkdown})
+	Register(Format{Name: "html", Extension: ".html", ContentType: "text/html; charset=utf-8", Write: writeHTML})
+	Register(Format{Name: "junit", Extension: ".xml", ContentType: "application/xml", Write: writeJUnit})
+}
+
+func writeJSON(w io.Writer, r ScanResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("error marshalling JSON: %w", err)
+	}
+	return nil
+}
+
+// exposed reports whether a result is a reachable path whose name suggests
+// sensitive content — the cases JUnit fails and the HTML report highlights.
+func exposed(r types.Result) bool {
+	return r.Error == nil && classify.Effective(r.Class, r.StatusCode) == classify.Open && sensitive.Is(r.URL)
+}
+
+func errText(r types.Result) string {
+	if r.Error != nil {
+		return r.Error.Error()
+	}
+	return ""
+}
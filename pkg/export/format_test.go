@@ -0,0 +1,123 @@
+package export_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/export"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+func sampleScan() export.ScanResult {
+	return export.CreateScanResult("example.com", time.Second, []types.Result{
+		{URL: "http://example.com/admin", StatusCode: 200, Status: "200 OK", Class: "open"},
+		{URL: "http://example.com/docs", StatusCode: 200, Status: "200 OK", Class: "open"},
+		{URL: "http://example.com/backup", StatusCode: 403, Status: "403 Forbidden", Class: "forbidden"},
+		{URL: "http://example.com/slow", Error: errors.New("timeout")},
+	}, false)
+}
+
+func TestFormatsRegistered(t *testing.T) {
+	for _, name := range []string{"json", "csv", "markdown", "html", "junit"} {
+		if _, ok := export.Lookup(name); !ok {
+			t.Errorf("format %q not registered", name)
+		}
+	}
+	var sb strings.Builder
+	if err := export.Write(&sb, "pdf", sampleScan()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var sb strings.Builder
+	if err := export.Write(&sb, "csv", sampleScan()); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 5 || rows[4][7] != "timeout" {
+		t.Errorf("unexpected CSV rows: %v", rows)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var sb strings.Builder
+	if err := export.Write(&sb, "junit", sampleScan()); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Name    string    `xml:"name,attr"`
+				Failure *struct{} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal([]byte(sb.String()), &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	// Only the reachable /admin fails: /docs isn't sensitive, /backup is 403.
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 1 {
+		t.Errorf("tests/failures/errors = %d/%d/%d, want 4/1/1", doc.Tests, doc.Failures, doc.Errors)
+	}
+	for _, c := range doc.Suites[0].Cases {
+		if (c.Failure != nil) != strings.HasSuffix(c.Name, "/admin") {
+			t.Errorf("case %s: failure=%v", c.Name, c.Failure != nil)
+		}
+	}
+}
+
+func TestWriteMarkdownAndHTML(t *testing.T) {
+	for _, format := range []string{"markdown", "html"} {
+		var sb strings.Builder
+		if err := export.Write(&sb, format, sampleScan()); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !strings.Contains(sb.String(), "http://example.com/backup") {
+			t.Errorf("%s report missing a result:\n%s", format, sb.String())
+		}
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	var sb strings.Builder
+	nd := export.NewNDJSON(&sb)
+	scan := sampleScan()
+	for _, r := range scan.Results {
+		if err := nd.Result("example.com", r, 1500*time.Microsecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := nd.Summary(scan, nil); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	if len(lines) != len(scan.Results)+1 {
+		t.Fatalf("expected %d lines, got %d", len(scan.Results)+1, len(lines))
+	}
+	var first export.ResultLine
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Type != "result" || first.Target != "example.com" || first.ElapsedMS != 1.5 {
+		t.Errorf("unexpected result line: %+v", first)
+	}
+	var last export.SummaryLine
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Type != "summary" || last.TotalPaths != 4 || last.Errors != 1 {
+		t.Errorf("unexpected summary line: %+v", last)
+	}
+}
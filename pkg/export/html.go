@@ -0,0 +1,50 @@
+package export
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlTmpl is self-contained: inline CSS, no scripts or external assets, so
+// the report can be attached to a ticket or opened offline.
+var htmlTmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"exposed": exposed,
+	"errText": errText,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Parsero report for {{.URL}}</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:2rem;color:#222}
+table{border-collapse:collapse;width:100%}
+th,td{border-bottom:1px solid #ddd;padding:.4rem .6rem;text-align:left;font-size:.9rem}
+th{background:#f4f4f4}
+tr.exposed td{background:#fdecea}
+.ok{color:#1a7f37}.bad{color:#cf222e}.warn{color:#9a6700}
+</style>
+</head>
+<body>
+<h1>Parsero report for {{.URL}}</h1>
+<p>Scanned {{.Timestamp}} in {{printf "%.2f" .Duration}}s &middot;
+{{.TotalPaths}} paths: <span class="ok">{{.Status200}} &times; 200</span>,
+{{.OtherStatus}} other, <span class="bad">{{.Errors}} errors</span></p>
+{{if .Degraded}}<p class="warn"><strong>Degraded:</strong> {{.DegradedReason}}</p>{{end}}
+<table>
+<thead><tr><th>URL</th><th>Status</th><th>Access</th><th>Technology</th></tr></thead>
+<tbody>
+{{range .Results}}<tr{{if exposed .}} class="exposed"{{end}}>
+<td>{{.URL}}</td>
+<td>{{if .Error}}<span class="bad">{{errText .}}</span>{{else}}{{.Status}}{{end}}</td>
+<td>{{.Class}}{{if .AuthScheme}} ({{.AuthScheme}}){{end}}</td>
+<td>{{range $i, $f := .Fingerprints}}{{if $i}}, {{end}}{{$f}}{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, r ScanResult) error {
+	return htmlTmpl.Execute(w, r)
+}
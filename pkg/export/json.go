@@ -5,21 +5,39 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/zvdy/parsero-go/pkg/lint"
 	"github.com/zvdy/parsero-go/pkg/types"
 )
 
 // ScanResult represents the complete result of a parsero scan
 type ScanResult struct {
-	Timestamp   string         `json:"timestamp"`
-	URL         string         `json:"url"`
-	Duration    float64        `json:"duration_seconds"`
-	Results     []types.Result `json:"results"`
-	TotalPaths  int            `json:"total_paths"`
-	Status200   int            `json:"status_200"`
-	OtherStatus int            `json:"other_status"`
-	Errors      int            `json:"errors"`
+	Timestamp string         `json:"timestamp"`
+	URL       string         `json:"url"`
+	Duration  float64        `json:"duration_seconds"`
+	Results   []types.Result `json:"results"`
+	// Disallow is the robots.txt entry list the results were probed from.
+	Disallow    []string `json:"disallow,omitempty"`
+	TotalPaths  int      `json:"total_paths"`
+	Status200   int      `json:"status_200"`
+	OtherStatus int      `json:"other_status"`
+	Errors      int      `json:"errors"`
+	// Degraded is set when WAF or bot-protection responses dominated the scan,
+	// so the results likely don't reflect what's really exposed.
+	Degraded       bool   `json:"degraded,omitempty"`
+	DegradedReason string `json:"degraded_reason,omitempty"`
+	// Concurrency is the worker-pool profile the scan ran with.
+	Concurrency *types.ConcurrencyProfile `json:"concurrency,omitempty"`
+	// Policy is the --fail-on decision, when a policy was given.
+	Policy *types.PolicyDecision `json:"policy,omitempty"`
+	// Lint is the robots.txt lint report of a --no-probe run, which has no
+	// results.
+	Lint []lint.Finding `json:"lint,omitempty"`
+	// Targets is each target's own summary in a report Merge combined from
+	// several; their results are all in Results.
+	Targets []ScanResult `json:"targets,omitempty"`
 }
 
 // ToJSON converts a ScanResult to a JSON string
@@ -47,6 +65,20 @@ func SaveToFile(results ScanResult, filePath string) error {
 	return nil
 }
 
+// LoadFile reads a ScanResult previously written by SaveToFile or the json
+// format.
+func LoadFile(filePath string) (ScanResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("error reading file: %w", err)
+	}
+	var r ScanResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		return ScanResult{}, fmt.Errorf("error parsing %s: %w", filePath, err)
+	}
+	return r, nil
+}
+
 // CreateScanResult creates a new ScanResult from the scan data
 func CreateScanResult(url string, duration time.Duration, results []types.Result, only200 bool) ScanResult {
 	// If only200 flag is set, filter results to only include 200 status codes
@@ -82,3 +114,36 @@ func CreateScanResult(url string, duration time.Duration, results []types.Result
 
 	return scanResult
 }
+
+// Merge combines the reports of a multi-target scan into one: results and
+// their counts are concatenated, Duration is the total scan time, and Targets
+// keeps each report's summary. A single report is returned as is.
+func Merge(reports []ScanResult) ScanResult {
+	if len(reports) == 1 {
+		return reports[0]
+	}
+	m := ScanResult{Results: []types.Result{}}
+	var first time.Time
+	urls := make([]string, 0, len(reports))
+	var degraded []string
+	for _, r := range reports {
+		if at, err := time.Parse(time.RFC3339, r.Timestamp); err == nil && (first.IsZero() || at.Before(first)) {
+			first, m.Timestamp = at, r.Timestamp
+		}
+		urls = append(urls, r.URL)
+		m.Duration += r.Duration
+		m.Results = append(m.Results, r.Results...)
+		m.TotalPaths += r.TotalPaths
+		m.Status200 += r.Status200
+		m.OtherStatus += r.OtherStatus
+		m.Errors += r.Errors
+		if r.Degraded {
+			degraded = append(degraded, r.URL+": "+r.DegradedReason)
+		}
+		r.Results = nil
+		m.Targets = append(m.Targets, r)
+	}
+	m.URL = strings.Join(urls, ", ")
+	m.Degraded, m.DegradedReason = len(degraded) > 0, strings.Join(degraded, "; ")
+	return m
+}
@@ -2,7 +2,9 @@ package export_test
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -176,3 +178,58 @@ func TestCreateScanResult(t *testing.T) {
 		t.Errorf("With only200=true, expected only status 200 results, got %d results", len(scanResult.Results))
 	}
 }
+
+func TestLoadFileRoundTrip(t *testing.T) {
+	results := []types.Result{
+		{URL: "http://x/admin", StatusCode: 200, Status: "200 OK", Class: "open"},
+		{URL: "http://x/slow", Error: errors.New("timeout")},
+	}
+	sr := export.CreateScanResult("x", time.Second, results, false)
+	sr.Disallow = []string{"admin", "slow"}
+
+	path := filepath.Join(t.TempDir(), "scan.json")
+	if err := export.SaveToFile(sr, path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := export.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if got.URL != "x" || len(got.Disallow) != 2 || len(got.Results) != 2 {
+		t.Fatalf("unexpected round trip: %+v", got)
+	}
+	if got.Results[1].Error == nil || got.Results[1].Error.Error() != "timeout" {
+		t.Errorf("error lost in round trip: %+v", got.Results[1])
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := export.CreateScanResult("a.example", time.Second, []types.Result{
+		{URL: "http://a.example/admin", StatusCode: 200, Status: "200 OK"},
+	}, false)
+	a.Timestamp = "2026-01-02T10:00:00Z"
+	b := export.CreateScanResult("b.example", 2*time.Second, []types.Result{
+		{URL: "http://b.example/x", StatusCode: 404, Status: "404 Not Found"},
+		{URL: "http://b.example/y", Error: errors.New("timeout")},
+	}, false)
+	b.Timestamp = "2026-01-02T09:00:00Z"
+	b.Degraded, b.DegradedReason = true, "mostly WAF blocks"
+
+	m := export.Merge([]export.ScanResult{a, b})
+	if m.URL != "a.example, b.example" || m.Timestamp != b.Timestamp || m.Duration != 3 {
+		t.Errorf("merged header %q %q %v", m.URL, m.Timestamp, m.Duration)
+	}
+	if len(m.Results) != 3 || m.TotalPaths != 3 || m.Status200 != 1 || m.OtherStatus != 1 || m.Errors != 1 {
+		t.Errorf("merged counts %+v", m)
+	}
+	if !m.Degraded || m.DegradedReason != "b.example: mostly WAF blocks" {
+		t.Errorf("degraded %v %q", m.Degraded, m.DegradedReason)
+	}
+	if len(m.Targets) != 2 || m.Targets[1].URL != "b.example" || m.Targets[1].Errors != 1 || m.Targets[1].Results != nil {
+		t.Errorf("targets %+v", m.Targets)
+	}
+
+	if got := export.Merge([]export.ScanResult{a}); got.URL != a.URL || got.Targets != nil {
+		t.Errorf("single report changed: %+v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     float64      `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Time      float64     `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit emits one test case per probed path. A reachable path whose name
+// looks sensitive fails; transport errors are JUnit errors; everything else
+// passes, so CI dashboards chart exposures over time.
+func writeJUnit(w io.Writer, r ScanResult) error {
+	suite := junitSuite{Name: r.URL, Tests: len(r.Results), Time: r.Duration, Timestamp: r.Timestamp}
+	for _, res := range r.Results {
+		tc := junitCase{Name: res.URL, ClassName: "parsero." + r.URL}
+		switch {
+		case res.Error != nil:
+			tc.Error = &junitMessage{Message: res.Error.Error(), Type: "probe-error"}
+			suite.Errors++
+		case exposed(res):
+			tc.Failure = &junitMessage{
+				Message: "sensitive Disallow path is reachable",
+				Type:    "exposed-disallow-path",
+				Text:    res.URL + " returned " + res.Status,
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	doc := junitSuites{
+		Name: "parsero", Tests: suite.Tests, Failures: suite.Failures, Errors: suite.Errors,
+		Time: r.Duration, Suites: []junitSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
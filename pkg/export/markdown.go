@@ -0,0 +1,37 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func writeMarkdown(w io.Writer, r ScanResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Parsero report for %s\n\n", r.URL)
+	fmt.Fprintf(&b, "- Scanned: %s\n- Duration: %.2fs\n", r.Timestamp, r.Duration)
+	fmt.Fprintf(&b, "- Paths: %d (%d × 200, %d other, %d errors)\n", r.TotalPaths, r.Status200, r.OtherStatus, r.Errors)
+	if r.Degraded {
+		fmt.Fprintf(&b, "- **Degraded:** %s\n", r.DegradedReason)
+	}
+	b.WriteString("\n| URL | Status | Access | Technology |\n|---|---|---|---|\n")
+	for _, res := range r.Results {
+		status := res.Status
+		if res.Error != nil {
+			status = "error: " + res.Error.Error()
+		}
+		url := mdEscape(res.URL)
+		if exposed(res) {
+			url = "**" + url + "**"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", url, mdEscape(status), res.Class, strings.Join(res.Fingerprints, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mdEscape keeps pipes and newlines from breaking the table.
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// ResultLine is one probe result in an NDJSON stream.
+type ResultLine struct {
+	Type         string             `json:"type"` // "result"
+	Time         string             `json:"time"`
+	Target       string             `json:"target"`
+	URL          string             `json:"url"`
+	Source       string             `json:"source,omitempty"`
+	Archived     *types.ArchiveSpan `json:"archived,omitempty"`
+	StatusCode   int                `json:"status_code,omitempty"`
+	Status       string             `json:"status,omitempty"`
+	Class        string             `json:"class,omitempty"`
+	AuthScheme   string             `json:"auth_scheme,omitempty"`
+	Fingerprints []string           `json:"fingerprints,omitempty"`
+	Error        string             `json:"error,omitempty"`
+	ElapsedMS    float64            `json:"elapsed_ms"`
+}
+
+// SummaryLine closes a target in an NDJSON stream. Error is set when the scan
+// failed outright (e.g. no robots.txt).
+type SummaryLine struct {
+	Type           string                    `json:"type"` // "summary"
+	Time           string                    `json:"time"`
+	Target         string                    `json:"target"`
+	Duration       float64                   `json:"duration_seconds"`
+	TotalPaths     int                       `json:"total_paths"`
+	Status200      int                       `json:"status_200"`
+	OtherStatus    int                       `json:"other_status"`
+	Errors         int                       `json:"errors"`
+	Degraded       bool                      `json:"degraded,omitempty"`
+	DegradedReason string                    `json:"degraded_reason,omitempty"`
+	Concurrency    *types.ConcurrencyProfile `json:"concurrency,omitempty"`
+	Policy         *types.PolicyDecision     `json:"policy,omitempty"`
+	Error          string                    `json:"error,omitempty"`
+}
+
+// NDJSON writes newline-delimited JSON, one object per line, as results
+// arrive. It is safe for concurrent use, so several targets can share one.
+type NDJSON struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{enc: json.NewEncoder(w)}
+}
+
+// Result emits a "result" line for r, probed on target in elapsed.
+func (n *NDJSON) Result(target string, r types.Result, elapsed time.Duration) error {
+	line := ResultLine{
+		Type:         "result",
+		Time:         time.Now().UTC().Format(time.RFC3339Nano),
+		Target:       target,
+		URL:          r.URL,
+		Source:       r.Source,
+		Archived:     r.Archived,
+		StatusCode:   r.StatusCode,
+		Status:       r.Status,
+		Class:        r.Class,
+		AuthScheme:   r.AuthScheme,
+		Fingerprints: r.Fingerprints,
+		Error:        errText(r),
+		ElapsedMS:    float64(elapsed.Microseconds()) / 1000,
+	}
+	return n.write(line)
+}
+
+// Summary emits a "summary" line for a finished target; scanErr is the fatal
+// scan error, if any.
+func (n *NDJSON) Summary(s ScanResult, scanErr error) error {
+	line := SummaryLine{
+		Type:           "summary",
+		Time:           time.Now().UTC().Format(time.RFC3339Nano),
+		Target:         s.URL,
+		Duration:       s.Duration,
+		TotalPaths:     s.TotalPaths,
+		Status200:      s.Status200,
+		OtherStatus:    s.OtherStatus,
+		Errors:         s.Errors,
+		Degraded:       s.Degraded,
+		DegradedReason: s.DegradedReason,
+		Concurrency:    s.Concurrency,
+		Policy:         s.Policy,
+	}
+	if scanErr != nil {
+		line.Error = scanErr.Error()
+	}
+	return n.write(line)
+}
+
+func (n *NDJSON) write(v any) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.enc.Encode(v)
+}
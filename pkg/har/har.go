@@ -0,0 +1,315 @@
+// Package har records a scan's HTTP traffic and writes it as an HTTP Archive
+// (HAR 1.2) file, for evidence and for replaying a finding in a browser or
+// proxy. A Recorder wraps the scanner's *http.Client:
+//
+//	rec := har.NewRecorder(0)
+//	s := scanner.New(scanner.WithHTTPClient(rec.Client(client)))
+//	s.Run(ctx, target)
+//	rec.Write(f)
+//
+// Each entry records the request and response headers, timings, status and
+// up to the body limit of the response body, plus the scanner's traffic kind
+// (robots, probe or search) as "_kind". Credential headers are redacted.
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+// DefaultBodyLimit caps each recorded response body.
+const DefaultBodyLimit = 64 << 10
+
+// timeFormat is ISO 8601 with milliseconds, as HAR viewers expect.
+const timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// redacted replaces the value of a header in Recorder.Redact.
+const redacted = "[redacted]"
+
+// File is a HAR document.
+type File struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is one request/response pair. A request that got no response has
+// status 0 and the failure in Error.
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"` // ms
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+	Kind            string   `json:"_kind,omitempty"`
+	Error           string   `json:"_error,omitempty"`
+
+	started time.Time
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// Cookie is part of the format; cookies are recorded only as (redacted)
+// Cookie and Set-Cookie headers.
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Content is the recorded response body. Text is base64 when the body isn't
+// UTF-8, and cut at the recorder's body limit, noted in Comment.
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Timings are in milliseconds. Wait runs from sending the request to the
+// response headers; Receive covers reading the recorded part of the body.
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Recorder captures exchanges from the clients it wraps. It is safe for
+// concurrent use.
+type Recorder struct {
+	// Redact lists headers whose values are replaced before recording;
+	// NewRecorder fills in the usual credential headers.
+	Redact []string
+
+	bodyLimit int64
+	mu        sync.Mutex
+	entries   []Entry
+}
+
+// NewRecorder returns a recorder keeping up to bodyLimit bytes of each
+// response body; 0 means DefaultBodyLimit.
+func NewRecorder(bodyLimit int64) *Recorder {
+	if bodyLimit <= 0 {
+		bodyLimit = DefaultBodyLimit
+	}
+	return &Recorder{
+		Redact:    []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+		bodyLimit: bodyLimit,
+	}
+}
+
+// Client returns a copy of c (or of http.DefaultClient, if nil) whose
+// requests are recorded.
+func (r *Recorder) Client(c *http.Client) *http.Client {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	cp := *c
+	cp.Transport = r.Transport(c.Transport)
+	return &cp
+}
+
+// Transport wraps base (nil for http.DefaultTransport) so its exchanges are
+// recorded. Wrap the innermost transport to record headers other transports
+// add.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return transport{r, base}
+}
+
+type transport struct {
+	rec  *Recorder
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	wait := time.Since(start)
+
+	e := Entry{
+		StartedDateTime: start.UTC().Format(timeFormat),
+		Request:         t.rec.request(req),
+		Timings:         Timings{Wait: ms(wait)},
+		Kind:            scanner.TrafficOf(req),
+		started:         start,
+	}
+	if err != nil {
+		e.Response = Response{Cookies: []Cookie{}, Headers: []NameValue{}, HeadersSize: -1, BodySize: -1}
+		e.Error = err.Error()
+		e.Time = ms(wait)
+		t.rec.add(e)
+		return nil, err
+	}
+
+	// Read the recorded part of the body now and hand the caller a reader
+	// that replays it before the rest.
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, t.rec.bodyLimit+1))
+	resp.Body = replayBody{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	e.Timings.Receive = ms(time.Since(start) - wait)
+	e.Time = ms(time.Since(start))
+	e.Response = t.rec.response(resp, body)
+	t.rec.add(e)
+	return resp, nil
+}
+
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+func (r *Recorder) request(req *http.Request) Request {
+	query := []NameValue{}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			query = append(query, NameValue{name, v})
+		}
+	}
+	sort.Slice(query, func(i, j int) bool { return query[i].Name < query[j].Name })
+	return Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []Cookie{},
+		Headers:     r.headers(req.Header),
+		QueryString: query,
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+}
+
+func (r *Recorder) response(resp *http.Response, body []byte) Response {
+	content := Content{
+		Size:     int64(len(body)),
+		MimeType: resp.Header.Get("Content-Type"),
+	}
+	if int64(len(body)) > r.bodyLimit {
+		body = body[:r.bodyLimit]
+		content.Size = resp.ContentLength // -1 if unknown
+		content.Comment = "truncated"
+	}
+	if utf8.Valid(body) {
+		content.Text = string(body)
+	} else {
+		content.Text, content.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	return Response{
+		Status:      resp.StatusCode,
+		StatusText:  strings.TrimSpace(strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode))),
+		HTTPVersion: resp.Proto,
+		Cookies:     []Cookie{},
+		Headers:     r.headers(resp.Header),
+		Content:     content,
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    resp.ContentLength,
+	}
+}
+
+func (r *Recorder) headers(h http.Header) []NameValue {
+	out := []NameValue{}
+	for name, values := range h {
+		for _, v := range values {
+			for _, red := range r.Redact {
+				if strings.EqualFold(name, red) {
+					v = redacted
+				}
+			}
+			out = append(out, NameValue{name, v})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (r *Recorder) add(e Entry) {
+	r.mu.Lock()
+	r.entries = append(r.entries, e)
+	r.mu.Unlock()
+}
+
+// Len is the number of exchanges recorded so far.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// HAR returns what has been recorded so far, oldest request first.
+func (r *Recorder) HAR() File {
+	r.mu.Lock()
+	entries := append([]Entry(nil), r.entries...)
+	r.mu.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].started.Before(entries[j].started) })
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return File{Log: Log{Version: "1.2", Creator: creator(), Entries: entries}}
+}
+
+// Write writes the recording as a HAR file.
+func (r *Recorder) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.HAR())
+}
+
+func creator() Creator {
+	c := Creator{Name: "parsero", Version: "devel"}
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		c.Version = bi.Main.Version
+	}
+	return c
+}
+
+func ms(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
@@ -0,0 +1,99 @@
+package har_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zvdy/parsero-go/pkg/har"
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+func TestRecordScan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /login/\nDisallow: /big/\n"))
+		case "/login/":
+			w.Header().Set("Set-Cookie", "session=abc")
+			w.Write([]byte(`<form><input type="password"></form>`))
+		case "/big/":
+			w.Write(bytes.Repeat([]byte("x"), 100))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	rec := har.NewRecorder(64)
+	s := scanner.New(
+		scanner.WithHTTPClient(rec.Client(srv.Client())),
+		scanner.WithConcurrency(1),
+		scanner.WithInspectBody(),
+		scanner.OnRequest(func(_ string, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer secret")
+			return nil
+		}),
+	)
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The recorder replays the body it read, so classification still works.
+	for _, r := range results {
+		if strings.HasSuffix(r.URL, "/login/") && r.Class != scanner.ClassLogin {
+			t.Errorf("/login/ classified %q through the recorder", r.Class)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := rec.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var f har.File
+	if err := json.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatalf("invalid HAR: %v", err)
+	}
+	if f.Log.Version != "1.2" || f.Log.Creator.Name != "parsero" {
+		t.Errorf("log header %+v", f.Log)
+	}
+	// robots.txt, then HEAD and a GET re-fetch per path.
+	if len(f.Log.Entries) != 5 || rec.Len() != 5 {
+		t.Fatalf("%d entries, want 5", len(f.Log.Entries))
+	}
+	if e := f.Log.Entries[0]; e.Kind != scanner.TrafficRobots || !strings.Contains(e.Response.Content.Text, "Disallow: /login/") {
+		t.Errorf("first entry %+v", e)
+	}
+	for _, e := range f.Log.Entries {
+		for _, h := range append(e.Request.Headers, e.Response.Headers...) {
+			if (h.Name == "Authorization" || h.Name == "Set-Cookie") && h.Value != "[redacted]" {
+				t.Errorf("%s recorded in the clear: %q", h.Name, h.Value)
+			}
+		}
+		if strings.HasSuffix(e.Request.URL, "/big/") && e.Request.Method == http.MethodGet {
+			if c := e.Response.Content; len(c.Text) != 64 || c.Comment != "truncated" || c.Size != 100 {
+				t.Errorf("big body recorded as %d bytes, comment %q, size %d", len(c.Text), c.Comment, c.Size)
+			}
+		}
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	rec := har.NewRecorder(0)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close() // nothing listening now
+
+	if _, err := rec.Client(nil).Get(url); err == nil {
+		t.Fatal("expected a connection error")
+	}
+	entries := rec.HAR().Log.Entries
+	if len(entries) != 1 || entries[0].Response.Status != 0 || entries[0].Error == "" {
+		t.Errorf("entries %+v", entries)
+	}
+}
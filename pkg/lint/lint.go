@@ -0,0 +1,289 @@
+// Package lint checks a robots.txt for mistakes and for information it leaks:
+// syntax errors, unknown directives, rules outside any group, conflicting,
+// unreachable or shadowed rules, oversized files, and paths or comments that give away
+// sensitive names, emails, internal hosts or ticket IDs. Every finding carries
+// a stable rule ID and a 1-based line number.
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zvdy/parsero-go/pkg/sensitive"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+// MaxSize is the largest robots.txt Google processes; content past it is
+// silently ignored by crawlers.
+const MaxSize = 500 << 10
+
+// Rule is one check of the catalogue, as SARIF reports describe it.
+type Rule struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// Finding is one problem Lint found, at a 1-based line.
+type Finding struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// Rules is the catalogue every Finding.RuleID refers to.
+var Rules = []Rule{
+	{"syntax-error", "SyntaxError", SeverityError, "Line is not a `field: value` pair."},
+	{"unknown-directive", "UnknownDirective", SeverityWarning, "Directive is not understood by major crawlers."},
+	{"rule-outside-group", "RuleOutsideGroup", SeverityError, "Allow/Disallow appears before any User-agent line, so no crawler applies it."},
+	{"conflicting-rules", "ConflictingRules", SeverityWarning, "The same path is both allowed and disallowed in one group."},
+	{"unreachable-rule", "UnreachableRule", SeverityWarning, "Path doesn't start with `/` or `*`, so it can never match a URL."},
+	{"duplicate-rule", "DuplicateRule", SeverityNote, "Rule repeats an earlier rule in the same group."},
+	{"shadowed-rule", "ShadowedRule", SeverityNote, "A broader rule of the same kind in the group already covers every URL the rule matches."},
+	{"oversized-file", "OversizedFile", SeverityError, "File exceeds 500 KiB; crawlers ignore everything past the limit."},
+	{"sensitive-path", "SensitivePath", SeverityWarning, "Rule advertises a path whose name suggests sensitive content."},
+	{"comment-leak", "CommentLeak", SeverityWarning, "Comment discloses an email address, internal hostname or ticket ID."},
+}
+
+var ruleSeverity = func() map[string]string {
+	m := make(map[string]string, len(Rules))
+	for _, r := range Rules {
+		m[r.ID] = r.Severity
+	}
+	return m
+}()
+
+// knownDirectives are the fields Google, Bing and Yandex act on.
+var knownDirectives = map[string]bool{
+	"user-agent": true, "allow": true, "disallow": true, "sitemap": true,
+	"crawl-delay": true, "host": true, "clean-param": true,
+}
+
+var (
+	emailRe    = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	internalRe = regexp.MustCompile(`(?i)\b[a-z0-9-]+(\.[a-z0-9-]+)*\.(internal|local|corp|lan|intranet|localdomain)\b|\b(10|127)\.\d{1,3}\.\d{1,3}\.\d{1,3}\b|\b192\.168\.\d{1,3}\.\d{1,3}\b|\b172\.(1[6-9]|2\d|3[01])\.\d{1,3}\.\d{1,3}\b`)
+	ticketRe   = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-\d{1,6}\b`)
+)
+
+// notTickets are prefixes of standard names shaped like ticket IDs
+// (UTF-8, SHA-256, ISO-8601, RFC-9309, CVE-2024).
+var notTickets = map[string]bool{
+	"AES": true, "ANSI": true, "CP": true, "CVE": true, "CWE": true, "ECMA": true,
+	"FIPS": true, "HTTP": true, "IEC": true, "IEEE": true, "ISO": true, "MD": true,
+	"MPEG": true, "PKCS": true, "RFC": true, "RSA": true, "SHA": true, "TLS": true,
+	"UCS": true, "UTF": true, "X11": true,
+}
+
+type rule struct {
+	allow bool
+	path  string
+	line  int
+}
+
+func (r rule) name() string {
+	if r.allow {
+		return "Allow"
+	}
+	return "Disallow"
+}
+
+// prefix is the path the rule matches by prefix, ignoring a trailing "*".
+func (r rule) prefix() string {
+	return strings.TrimRight(r.path, "*")
+}
+
+// Lint returns the findings for data in line order.
+func Lint(data []byte) []Finding {
+	var out []Finding
+	add := func(id string, line int, format string, args ...any) {
+		out = append(out, Finding{RuleID: id, Severity: ruleSeverity[id], Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var (
+		inGroup    bool // seen a User-agent line
+		lastWasUA  bool
+		groupRules []rule
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64<<10), len(data)+1)
+	// The line holding the first byte past MaxSize, counted the way the
+	// scanner splits lines.
+	oversizeLine := 0
+	if len(data) > MaxSize {
+		oversizeLine = bytes.Count(data[:MaxSize], []byte("\n")) + 1
+	}
+	for n := 1; sc.Scan(); n++ {
+		raw := sc.Text()
+		if n == oversizeLine {
+			add("oversized-file", n, "robots.txt is %d bytes; crawlers stop reading at %d (this line)", len(data), MaxSize)
+		}
+
+		line, comment, hasComment := strings.Cut(raw, "#")
+		if hasComment {
+			lintComment(comment, n, add)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			add("syntax-error", n, "expected `field: value`, got %q", line)
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		if !knownDirectives[field] {
+			add("unknown-directive", n, "unknown directive %q", field)
+			lastWasUA = false
+			continue
+		}
+
+		switch field {
+		case "user-agent":
+			if !lastWasUA {
+				// A User-agent after rules starts a new group.
+				checkShadowed(groupRules, add)
+				groupRules = nil
+			}
+			inGroup, lastWasUA = true, true
+			continue
+		case "allow", "disallow":
+			lastWasUA = false
+			if !inGroup {
+				add("rule-outside-group", n, "%s rule before any User-agent line is ignored", directiveName(field))
+				continue
+			}
+			if value == "" {
+				continue // "Disallow:" with no path means allow everything
+			}
+			r := rule{allow: field == "allow", path: value, line: n}
+			checkRule(r, groupRules, add)
+			groupRules = append(groupRules, r)
+		default:
+			lastWasUA = false
+		}
+	}
+	checkShadowed(groupRules, add)
+	// Shadowing is only known once a group is complete.
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Line < out[j].Line })
+	return out
+}
+
+func checkRule(r rule, earlier []rule, add func(string, int, string, ...any)) {
+	name := r.name()
+	if !strings.HasPrefix(r.path, "/") && !strings.HasPrefix(r.path, "*") {
+		add("unreachable-rule", r.line, "%s path %q doesn't start with / or * and never matches", name, r.path)
+	}
+	for _, e := range earlier {
+		if e.path != r.path {
+			continue
+		}
+		if e.allow == r.allow {
+			add("duplicate-rule", r.line, "%s %s repeats line %d", name, r.path, e.line)
+		} else {
+			add("conflicting-rules", r.line, "%s %s contradicts line %d", name, r.path, e.line)
+		}
+		break
+	}
+	if !r.allow {
+		if m, ok := sensitive.Match(r.path); ok {
+			add("sensitive-path", r.line, "Disallow %s advertises a sensitive-looking path (%q)", r.path, m)
+		}
+	}
+}
+
+// checkShadowed reports the rules of a group that can't change any decision.
+// Crawlers apply the longest matching rule whatever the order, so a rule is
+// shadowed when a shorter wildcard-free rule of the same kind covers it and
+// no rule of the other kind could win over that one for a URL it matches.
+func checkShadowed(group []rule, add func(string, int, string, ...any)) {
+	// Candidate broader rules by kind and prefix; the first one listed wins.
+	prefixes := map[bool]map[string]rule{true: {}, false: {}}
+	for _, b := range group {
+		p := b.prefix()
+		if _, seen := prefixes[b.allow][p]; !seen && reachable(b.path) && literal(p) == p {
+			prefixes[b.allow][p] = b
+		}
+	}
+	for _, r := range group {
+		if !reachable(r.path) {
+			continue
+		}
+		lit := literal(r.path)
+		for i := 0; i <= len(lit) && i < len(r.prefix()); i++ {
+			b, ok := prefixes[r.allow][lit[:i]]
+			if !ok {
+				continue
+			}
+			if !overridden(r, i, group) {
+				add("shadowed-rule", r.line, "%s %s is already covered by line %d (%s %s)",
+					r.name(), r.path, b.line, b.name(), b.path)
+			}
+			break
+		}
+	}
+}
+
+// overridden reports whether a rule of the other kind than r, at least as
+// long as r's broader rule (minLen), may match some URL r matches.
+func overridden(r rule, minLen int, group []rule) bool {
+	lit := literal(r.path)
+	for _, o := range group {
+		if o.allow == r.allow || !reachable(o.path) || len(o.path) < minLen {
+			continue
+		}
+		if literal(o.path) != o.path || strings.HasPrefix(lit, o.path) ||
+			(lit != r.path && strings.HasPrefix(o.path, lit)) {
+			return true
+		}
+	}
+	return false
+}
+
+// literal is the part of a rule path before its first wildcard or anchor.
+func literal(path string) string {
+	if i := strings.IndexAny(path, "*$"); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func reachable(path string) bool {
+	return strings.HasPrefix(path, "/") || strings.HasPrefix(path, "*")
+}
+
+func lintComment(comment string, line int, add func(string, int, string, ...any)) {
+	if m := emailRe.FindString(comment); m != "" {
+		add("comment-leak", line, "comment discloses email address %q", m)
+	}
+	if m := internalRe.FindString(comment); m != "" {
+		add("comment-leak", line, "comment discloses internal host %q", m)
+	}
+	for _, m := range ticketRe.FindAllString(comment, -1) {
+		if prefix, _, _ := strings.Cut(m, "-"); !notTickets[prefix] {
+			add("comment-leak", line, "comment references ticket %q", m)
+			break
+		}
+	}
+}
+
+func directiveName(field string) string {
+	if field == "allow" {
+		return "Allow"
+	}
+	return "Disallow"
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func ruleLines(fs []Finding) map[string][]int {
+	m := map[string][]int{}
+	for _, f := range fs {
+		m[f.RuleID] = append(m[f.RuleID], f.Line)
+	}
+	return m
+}
+
+func TestLint(t *testing.T) {
+	robots := strings.Join([]string{
+		"Disallow: /early",                  // 1 rule-outside-group
+		"User-agent: *",                     // 2
+		"Disallow: /admin",                  // 3 sensitive-path
+		"Allow: /admin",                     // 4 conflicting-rules
+		"Disallow: /docs",                   // 5
+		"Disallow: /docs",                   // 6 duplicate-rule
+		"Disallow: drafts",                  // 7 unreachable-rule
+		"Noindex: /x",                       // 8 unknown-directive
+		"garbage line",                      // 9 syntax-error
+		"# ask ops@example.com, see OPS-42", // 10 comment-leak x2
+		"# staging at build.corp",           // 11 comment-leak
+		"Sitemap: https://x/sitemap.xml",    // 12
+		"",
+		"User-agent: bot", // 14 new group
+		"Disallow: /docs", // 15 not a duplicate
+	}, "\n")
+	got := ruleLines(Lint([]byte(robots)))
+	want := map[string][]int{
+		"rule-outside-group": {1},
+		"sensitive-path":     {3},
+		"conflicting-rules":  {4},
+		"duplicate-rule":     {6},
+		"unreachable-rule":   {7},
+		"unknown-directive":  {8},
+		"syntax-error":       {9},
+		"comment-leak":       {10, 10, 11},
+	}
+	for id, lines := range want {
+		if len(got[id]) != len(lines) {
+			t.Errorf("%s: got lines %v, want %v", id, got[id], lines)
+			continue
+		}
+		for i := range lines {
+			if got[id][i] != lines[i] {
+				t.Errorf("%s: got lines %v, want %v", id, got[id], lines)
+				break
+			}
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("unexpected rules fired: %v", got)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	robots := "User-agent: *\nDisallow: /search\nAllow: /search/about\nSitemap: https://x/s.xml\n"
+	if fs := Lint([]byte(robots)); len(fs) != 0 {
+		t.Errorf("expected no findings, got %+v", fs)
+	}
+}
+
+func TestLintOversized(t *testing.T) {
+	big := "User-agent: *\n" + strings.Repeat("Disallow: /aaaaaaaaaaaaaaaaaaaa\n", MaxSize/30+10)
+	if got := ruleLines(Lint([]byte(big))); len(got["oversized-file"]) != 1 {
+		t.Errorf("expected one oversized-file finding, got %v", got["oversized-file"])
+	}
+}
+
+// Oversize is decided on the real byte count, whatever the line endings.
+func TestLintOversizedLineEndings(t *testing.T) {
+	const entry = "Disallow: /aaaaaaaaaaaaaaaaaaaa" // 31 bytes
+	crlf := "User-agent: *\r\n" + strings.Repeat(entry+"\r\n", MaxSize/33+1)
+	exact := "User-agent: *\n" + strings.Repeat(entry+"\n", (MaxSize-14)/32)
+	exact += "#" + strings.Repeat("x", MaxSize-len(exact)-1) // no final newline
+
+	tests := []struct {
+		name string
+		data string
+		want []int
+	}{
+		{"crlf", crlf, []int{strings.Count(crlf[:MaxSize], "\n") + 1}},
+		{"exactly MaxSize", exact, nil},
+		{"one byte over", exact + "x", []int{strings.Count(exact, "\n") + 1}},
+	}
+	for _, tt := range tests {
+		got := ruleLines(Lint([]byte(tt.data)))["oversized-file"]
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s (%d bytes): oversized-file at %v, want %v", tt.name, len(tt.data), got, tt.want)
+		}
+	}
+}
+
+func TestSeveritiesMatchCatalogue(t *testing.T) {
+	for _, f := range Lint([]byte("x\nDisallow: /a")) {
+		if f.Severity != ruleSeverity[f.RuleID] || f.Severity == "" {
+			t.Errorf("%s: severity %q", f.RuleID, f.Severity)
+		}
+	}
+}
+
+func TestLintShadowed(t *testing.T) {
+	tests := []struct {
+		rules string
+		want  []int // lines, counting "User-agent: *" as 1
+	}{
+		{"Disallow: /admin\nDisallow: /admin/users", []int{3}},
+		{"Disallow: /admin/users\nDisallow: /admin", []int{2}}, // order doesn't matter
+		{"Disallow: /\nDisallow: /private/", []int{3}},
+		{"Disallow: /a\nDisallow: /a/*.php", []int{3}},
+		{"Allow: /pub\nAllow: /pub/css", []int{3}},
+		{"Disallow: /a\nAllow: /a/b/c\nDisallow: /a/b", []int{4}}, // the Allow wins over both
+		{"Disallow: /a\nAllow: /a/b\nDisallow: /a/b/c", nil},      // re-disallows inside an exception
+		{"Disallow: /a\nAllow: /*.css\nDisallow: /a/b", nil},      // keeps /a/b/x.css disallowed
+		{"Disallow: /a*\nDisallow: /a", nil},
+		{"Disallow: /a\nUser-agent: bot\nDisallow: /a/b", nil},
+	}
+	for _, tt := range tests {
+		got := ruleLines(Lint([]byte("User-agent: *\n" + tt.rules)))["shadowed-rule"]
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%q: shadowed lines %v, want %v", tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestLintTicketIDs(t *testing.T) {
+	robots := "# UTF-8, SHA-256 sums, ISO-8601 dates per RFC-9309\n# moved in OPS-42\n"
+	var leaks []Finding
+	for _, f := range Lint([]byte(robots)) {
+		if f.RuleID == "comment-leak" {
+			leaks = append(leaks, f)
+		}
+	}
+	if len(leaks) != 1 || leaks[0].Line != 2 || !strings.Contains(leaks[0].Message, "OPS-42") {
+		t.Errorf("comment leaks %+v, want only OPS-42 on line 2", leaks)
+	}
+}
@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// aimd gates probes with an additive-increase/multiplicative-decrease limit:
+// one more slot after a window of clean probes, half the slots on a 429, 503,
+// transport error or a latency spike. It is created per CheckPaths call.
+type aimd struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	limit, inflight int
+	min, max        int
+	clean           int // clean completions since the last change
+	cooldown        int // completions to ignore after a decrease
+	baseline        time.Duration
+	samples         int
+	limitSum        int
+
+	prof ConcurrencyProfile
+}
+
+// spikeFactor is how far above the latency baseline a probe must be to count
+// as a sign the target is struggling.
+const spikeFactor = 3
+
+func newAIMD(initial, min, max int) *aimd {
+	initial = clamp(initial, min, max)
+	a := &aimd{
+		limit: initial, min: min, max: max,
+		prof: ConcurrencyProfile{
+			Mode: "adaptive", Min: min, Max: max,
+			Initial: initial, Peak: initial,
+		},
+	}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+func (a *aimd) acquire() {
+	a.mu.Lock()
+	for a.inflight >= a.limit {
+		a.cond.Wait()
+	}
+	a.inflight++
+	a.limitSum += a.limit
+	a.samples++
+	a.mu.Unlock()
+}
+
+func (a *aimd) release(latency time.Duration, r Result) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inflight--
+	defer a.cond.Broadcast()
+
+	overloaded := r.Error != nil ||
+		r.StatusCode == http.StatusTooManyRequests ||
+		r.StatusCode == http.StatusServiceUnavailable
+	if !overloaded {
+		if a.baseline == 0 {
+			a.baseline = latency
+		} else if latency > spikeFactor*a.baseline && a.samples > a.limit {
+			overloaded = true
+		} else {
+			// Slow EWMA so a gradual drift doesn't read as a spike.
+			a.baseline = (a.baseline*7 + latency) / 8
+		}
+	}
+
+	if a.cooldown > 0 {
+		a.cooldown--
+		return
+	}
+	if overloaded {
+		if next := clamp(a.limit/2, a.min, a.max); next < a.limit {
+			a.limit = next
+			a.prof.Decreases++
+		}
+		a.clean = 0
+		// Probes already in flight were sent at the old limit; don't punish twice.
+		a.cooldown = a.inflight
+		return
+	}
+	a.clean++
+	if a.clean >= a.limit && a.limit < a.max {
+		a.limit++
+		a.clean = 0
+		a.prof.Increases++
+		if a.limit > a.prof.Peak {
+			a.prof.Peak = a.limit
+		}
+	}
+}
+
+func (a *aimd) profile() ConcurrencyProfile {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := a.prof
+	p.Final = a.limit
+	if a.samples > 0 {
+		p.Mean = float64(a.limitSum) / float64(a.samples)
+	}
+	return p
+}
+
+func fixedProfile(n int) ConcurrencyProfile {
+	return ConcurrencyProfile{
+		Mode: "fixed", Min: n, Max: n, Initial: n, Final: n, Peak: n, Mean: float64(n),
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+func TestAIMDIncreasesAfterCleanWindow(t *testing.T) {
+	a := newAIMD(2, 1, 4)
+	ok := types.Result{StatusCode: 200}
+	for i := 0; i < 2; i++ {
+		a.acquire()
+		a.release(10*time.Millisecond, ok)
+	}
+	if p := a.profile(); p.Final != 3 || p.Increases != 1 {
+		t.Errorf("after one clean window: %+v, want final 3 with 1 increase", p)
+	}
+	for i := 0; i < 50; i++ {
+		a.acquire()
+		a.release(10*time.Millisecond, ok)
+	}
+	if p := a.profile(); p.Final != 4 || p.Peak != 4 {
+		t.Errorf("limit should cap at max: %+v", p)
+	}
+}
+
+func TestAIMDHalvesOnBackpressure(t *testing.T) {
+	a := newAIMD(8, 1, 8)
+	a.acquire()
+	a.release(10*time.Millisecond, types.Result{StatusCode: 429})
+	if p := a.profile(); p.Final != 4 || p.Decreases != 1 {
+		t.Errorf("after 429: %+v, want final 4 with 1 decrease", p)
+	}
+	a.acquire()
+	a.release(10*time.Millisecond, types.Result{Error: errors.New("timeout")})
+	a.acquire()
+	a.release(10*time.Millisecond, types.Result{Error: errors.New("timeout")})
+	a.acquire()
+	a.release(10*time.Millisecond, types.Result{Error: errors.New("timeout")})
+	if p := a.profile(); p.Final != 1 {
+		t.Errorf("repeated errors should floor at min: %+v", p)
+	}
+}
+
+func TestAIMDLatencySpike(t *testing.T) {
+	a := newAIMD(2, 1, 2)
+	ok := types.Result{StatusCode: 200}
+	for i := 0; i < 5; i++ {
+		a.acquire()
+		a.release(10*time.Millisecond, ok)
+	}
+	a.acquire()
+	a.release(time.Second, ok)
+	if p := a.profile(); p.Decreases != 1 || p.Final != 1 {
+		t.Errorf("latency spike should halve the limit: %+v", p)
+	}
+}
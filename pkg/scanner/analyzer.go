@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// Finding is an analyzer's annotation on a Result.
+type Finding = types.Finding
+
+// Finding.Severity values.
+const (
+	SeverityNote    = "note"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Response is what an Analyzer sees of a probe. Body holds at most
+// Options.BodyLimit bytes and is shared between analyzers, so they must not
+// modify it; Header is each analyzer's own copy.
+type Response struct {
+	URL        string // the probed URL
+	FinalURL   string // where redirects ended; "" if none were followed
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Class      string // the classifier's label, e.g. ClassOpen
+}
+
+// Analyzer is a post-probe check. Analyze is called once for every robots
+// probe that isn't a 404, after classification and fingerprinting, and its
+// findings are appended to the result. Implementations must be safe for
+// concurrent use and should return promptly once ctx is done.
+type Analyzer interface {
+	Name() string
+	Analyze(ctx context.Context, resp Response) ([]Finding, error)
+}
+
+// AnalyzerConfig registers an Analyzer in Options.Analyzers.
+type AnalyzerConfig struct {
+	Analyzer Analyzer
+	Timeout  time.Duration // per response; default 2s
+}
+
+const defaultAnalyzerTimeout = 2 * time.Second
+
+// WithAnalyzer appends an analyzer with its per-response timeout (0 for the
+// default).
+func WithAnalyzer(a Analyzer, timeout time.Duration) Option {
+	return func(s *Scanner) {
+		s.opts.Analyzers = append(s.opts.Analyzers, AnalyzerConfig{Analyzer: a, Timeout: timeout})
+	}
+}
+
+// OnAnalyzerError receives each analyzer failure: an error, a panic or a
+// timeout. The failure only drops that analyzer's findings for that response.
+func OnAnalyzerError(fn func(analyzer, url string, err error)) Option {
+	return func(s *Scanner) { s.onAnalyzerError = fn }
+}
+
+// analyze runs every registered analyzer on resp in turn, isolating each: a
+// panic, error or timeout in one doesn't touch the probe or the others.
+func (s *Scanner) analyze(ctx context.Context, resp Response) []Finding {
+	var findings []Finding
+	for _, ac := range s.opts.Analyzers {
+		r := resp
+		r.Header = resp.Header.Clone()
+		found, err := runAnalyzer(ctx, ac, r)
+		if err != nil {
+			if s.onAnalyzerError != nil {
+				s.onAnalyzerError(ac.Analyzer.Name(), resp.URL, err)
+			}
+			continue
+		}
+		for _, f := range found {
+			if f.Analyzer == "" {
+				f.Analyzer = ac.Analyzer.Name()
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// runAnalyzer calls one analyzer under its timeout. An analyzer that ignores
+// ctx is abandoned when the timeout passes, not waited for.
+func runAnalyzer(ctx context.Context, ac AnalyzerConfig, resp Response) ([]Finding, error) {
+	timeout := ac.Timeout
+	if timeout <= 0 {
+		timeout = defaultAnalyzerTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		findings []Finding
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- outcome{err: fmt.Errorf("panic: %v", p)}
+			}
+		}()
+		f, err := ac.Analyzer.Analyze(ctx, resp)
+		done <- outcome{f, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.findings, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
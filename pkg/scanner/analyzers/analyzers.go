@@ -0,0 +1,115 @@
+// Package analyzers holds parsero's built-in scanner.Analyzer
+// implementations, which double as examples for writing your own:
+//
+//	s := scanner.New(scanner.WithAnalyzer(analyzers.DirectoryListing(), 0))
+//
+// The CLI enables them by name with --analyze.
+package analyzers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+// Builtin returns a fresh instance of the named analyzer.
+func Builtin(name string) (scanner.Analyzer, error) {
+	switch name {
+	case "dirlist":
+		return DirectoryListing(), nil
+	case "secrets":
+		return Secrets(), nil
+	}
+	return nil, fmt.Errorf("unknown analyzer %q (want one of %v)", name, Names())
+}
+
+// Names lists the built-in analyzers, for help text and errors.
+func Names() []string {
+	return []string{"dirlist", "secrets"}
+}
+
+// dirList spots web-server generated directory indexes.
+type dirList struct{}
+
+// DirectoryListing flags open directory indexes (Apache, nginx, IIS and
+// Python's http.server autoindex pages), which expose every file under a
+// disallowed path.
+func DirectoryListing() scanner.Analyzer { return dirList{} }
+
+func (dirList) Name() string { return "dirlist" }
+
+var indexMarkers = [][]byte{
+	[]byte("<title>Index of /"),
+	[]byte("<h1>Index of /"),
+	[]byte("<title>Directory listing for /"), // python -m http.server
+	[]byte("[To Parent Directory]</A>"),      // IIS
+}
+
+func (dirList) Analyze(_ context.Context, resp scanner.Response) ([]scanner.Finding, error) {
+	if resp.StatusCode != 200 {
+		return nil, nil
+	}
+	for _, m := range indexMarkers {
+		if bytes.Contains(resp.Body, m) {
+			return []scanner.Finding{{
+				Message:  "directory listing is enabled",
+				Severity: scanner.SeverityWarning,
+				Tags:     []string{"exposure", "directory-listing"},
+				Evidence: string(m),
+			}}, nil
+		}
+	}
+	return nil, nil
+}
+
+// secrets looks for credentials in response bodies.
+type secrets struct{}
+
+// Secrets flags credentials served in the clear: private keys, AWS access
+// keys, GitHub and Slack tokens. Evidence is redacted to its first few
+// characters so reports don't re-leak the secret.
+func Secrets() scanner.Analyzer { return secrets{} }
+
+func (secrets) Name() string { return "secrets" }
+
+var secretPatterns = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+}
+
+func (secrets) Analyze(ctx context.Context, resp scanner.Response) ([]scanner.Finding, error) {
+	var findings []scanner.Finding
+	for _, p := range secretPatterns {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if m := p.re.Find(resp.Body); m != nil {
+			findings = append(findings, scanner.Finding{
+				Message:  p.kind + " exposed",
+				Severity: scanner.SeverityError,
+				Tags:     []string{"exposure", "secret"},
+				Evidence: redact(m),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// redact keeps enough of a match to identify it.
+func redact(m []byte) string {
+	if bytes.HasPrefix(m, []byte("-----")) {
+		return string(m) // a PEM header is not itself secret
+	}
+	if len(m) <= 8 {
+		return "****"
+	}
+	return string(m[:6]) + "****"
+}
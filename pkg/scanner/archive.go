@@ -0,0 +1,202 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// DefaultArchiveURL is the Wayback Machine. Any server with a
+// Wayback-compatible CDX API (/cdx/search/cdx) and replay endpoint (/web/)
+// can stand in, e.g. a pywb instance.
+const DefaultArchiveURL = "https://web.archive.org"
+
+// ArchiveSpan is when archived robots.txt versions listed an entry.
+type ArchiveSpan = types.ArchiveSpan
+
+// cdxTime is the CDX timestamp layout.
+const cdxTime = "20060102150405"
+
+// cdxLimit caps a CDX listing: a robots.txt crawled daily for decades.
+const cdxLimit = 16 << 20
+
+// archiveVersion is a run of consecutive captures of robots.txt with the
+// same content.
+type archiveVersion struct {
+	first, last time.Time
+	digest      string
+	timestamp   string // first capture, to fetch the content from
+	original    string
+}
+
+// archiveJobs lists the Disallow entries archived robots.txt versions list
+// but disallow doesn't, oldest first, at most max of them (0 = all). Like
+// Bing, a failing archive never fails the whole scan: it just contributes no
+// paths.
+func (s *Scanner) archiveJobs(ctx context.Context, target string, disallow []string, max int) []probeJob {
+	spans, err := s.archiveEntries(ctx, target)
+	if err != nil {
+		return nil
+	}
+	for _, p := range disallow {
+		delete(spans, p)
+	}
+	paths := make([]string, 0, len(spans))
+	for p := range spans {
+		paths = append(paths, p)
+	}
+	// Oldest first, so a MaxPaths cap keeps the longest-gone entries.
+	sort.Slice(paths, func(i, j int) bool {
+		a, b := spans[paths[i]], spans[paths[j]]
+		if !a.First.Equal(b.First) {
+			return a.First.Before(b.First)
+		}
+		return paths[i] < paths[j]
+	})
+	if max > 0 && len(paths) > max {
+		paths = paths[:max]
+	}
+	jobs := make([]probeJob, len(paths))
+	for i, p := range paths {
+		span := spans[p]
+		jobs[i] = probeJob{path: p, archived: &span}
+	}
+	return jobs
+}
+
+// archiveEntries returns every Disallow entry (leading slash stripped) of
+// target's archived robots.txt versions, with the span of captures that
+// listed it.
+func (s *Scanner) archiveEntries(ctx context.Context, target string) (map[string]ArchiveSpan, error) {
+	versions, err := s.archiveVersions(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Content recurs when a change is reverted; fetch each digest once, and
+	// when ArchiveVersions caps them, a sample spread over the whole history.
+	var digests []string
+	first := map[string]archiveVersion{}
+	for _, v := range versions {
+		if _, ok := first[v.digest]; !ok {
+			first[v.digest] = v
+			digests = append(digests, v.digest)
+		}
+	}
+	total := len(digests)
+	if n := s.opts.ArchiveVersions; n > 0 && len(digests) > n {
+		picked := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			idx := 0
+			if n > 1 {
+				idx = i * (len(digests) - 1) / (n - 1)
+			}
+			picked = append(picked, digests[idx])
+		}
+		digests = picked
+	}
+
+	entries := map[string][]string{}
+	for _, d := range digests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		body, err := s.archiveSnapshot(ctx, first[d])
+		if err != nil {
+			continue // one missing capture shouldn't hide the rest
+		}
+		entries[d], _ = parseDisallow(body, 0)
+	}
+	if s.onArchive != nil {
+		s.onArchive(len(entries), total)
+	}
+
+	spans := map[string]ArchiveSpan{}
+	for _, v := range versions {
+		for _, p := range entries[v.digest] {
+			span, ok := spans[p]
+			if !ok || v.first.Before(span.First) {
+				span.First = v.first
+			}
+			if v.last.After(span.Last) {
+				span.Last = v.last
+			}
+			spans[p] = span
+		}
+	}
+	return spans, nil
+}
+
+// archiveVersions lists target's archived robots.txt versions, oldest first,
+// from the archive's CDX API.
+func (s *Scanner) archiveVersions(ctx context.Context, target string) ([]archiveVersion, error) {
+	host := strings.TrimPrefix(strings.TrimPrefix(BaseURL(target), "http://"), "https://")
+	q := url.Values{
+		"url":    {host + "/robots.txt"},
+		"output": {"json"},
+		"fl":     {"timestamp,original,digest"},
+		"filter": {"statuscode:200"},
+	}
+	body, err := s.archiveGet(ctx, "/cdx/search/cdx?"+q.Encode(), cdxLimit)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("archive: invalid CDX response: %w", err)
+	}
+
+	var versions []archiveVersion
+	for i, row := range rows {
+		if i == 0 && len(row) > 0 && row[0] == "timestamp" {
+			continue // header
+		}
+		if len(row) < 3 {
+			continue
+		}
+		at, err := time.Parse(cdxTime, row[0])
+		if err != nil {
+			continue
+		}
+		if n := len(versions); n > 0 && versions[n-1].digest == row[2] {
+			versions[n-1].last = at
+			continue
+		}
+		versions = append(versions, archiveVersion{first: at, last: at, digest: row[2], timestamp: row[0], original: row[1]})
+	}
+	return versions, nil
+}
+
+// archiveSnapshot fetches one archived robots.txt as it was captured; the
+// "id_" modifier asks for the original bytes, without the replay banner.
+func (s *Scanner) archiveSnapshot(ctx context.Context, v archiveVersion) ([]byte, error) {
+	return s.archiveGet(ctx, "/web/"+v.timestamp+"id_/"+v.original, robotsLimit)
+}
+
+func (s *Scanner) archiveGet(ctx context.Context, path string, limit int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.ArchiveTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(s.opts.ArchiveURL, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", s.opts.UserAgent)
+	resp, err := s.do(TrafficArchive, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("archive: %s: %s", req.URL, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
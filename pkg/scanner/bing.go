@@ -8,12 +8,12 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/zvdy/parsero-go/pkg/types"
+	"github.com/zvdy/parsero-go/pkg/classify"
 )
 
 // searchBing probes target paths that Bing has indexed. Per-path errors are
 // swallowed so a flaky search engine never fails the whole scan.
-func (s *Scanner) searchBing(ctx context.Context, target string, paths []string) []types.Result {
+func (s *Scanner) searchBing(ctx context.Context, target string, paths []string) []Result {
 	if len(paths) == 0 {
 		return nil
 	}
@@ -25,7 +25,7 @@ func (s *Scanner) searchBing(ctx context.Context, target string, paths []string)
 	}
 
 	tasks := make(chan string, len(paths))
-	out := make(chan types.Result, len(paths)*5)
+	out := make(chan timed, len(paths)*5)
 
 	var wg sync.WaitGroup
 	for i := 0; i < concurrency; i++ {
@@ -50,16 +50,21 @@ func (s *Scanner) searchBing(ctx context.Context, target string, paths []string)
 		close(out)
 	}()
 
-	var results []types.Result
+	var results []Result
 	for r := range out {
-		results = append(results, r)
+		results = append(results, r.Result)
+		if s.onResult != nil {
+			s.onResult(r.Result, r.elapsed)
+		}
 	}
 	return results
 }
 
-func (s *Scanner) bingQuery(ctx context.Context, target, path string, out chan<- types.Result) {
-	disurl := "http://" + target + "/" + path
+func (s *Scanner) bingQuery(ctx context.Context, target, path string, out chan<- timed) {
+	disurl := BaseURL(target) + "/" + path
 	searchURL := "http://www.bing.com/search?q=site:" + disurl
+	host := strings.TrimPrefix(strings.TrimPrefix(disurl, "http://"), "https://")
+	host, _, _ = strings.Cut(host, "/")
 
 	// Light throttle to look less like a scraper.
 	select {
@@ -74,7 +79,7 @@ func (s *Scanner) bingQuery(ctx context.Context, target, path string, out chan<-
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(TrafficSearch, req)
 	if err != nil {
 		return
 	}
@@ -86,27 +91,36 @@ func (s *Scanner) bingQuery(ctx context.Context, target, path string, out chan<-
 	}
 	doc.Find("cite").Each(func(i int, sel *goquery.Selection) {
 		cite := sel.Text()
-		if strings.Contains(cite, target) {
-			out <- s.probeBingHit(ctx, cite)
+		if strings.Contains(cite, host) {
+			start := time.Now()
+			r := s.probeBingHit(ctx, cite)
+			out <- timed{r, time.Since(start)}
 		}
 	})
 }
 
-func (s *Scanner) probeBingHit(ctx context.Context, url string) types.Result {
+func (s *Scanner) probeBingHit(ctx context.Context, url string) Result {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return types.Result{URL: url, Error: err, Source: SourceBing}
+		return Result{URL: url, Error: err, Source: SourceBing}
 	}
-	resp, err := s.client.Do(req)
+	resp, err := s.do(TrafficSearch, req)
 	if err != nil {
-		return types.Result{URL: url, Error: err, Source: SourceBing}
+		return Result{URL: url, Error: err, Source: SourceBing}
 	}
 	defer resp.Body.Close()
 
-	return types.Result{
+	label := classify.Classify(classify.Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		URL:        redirectedTo(resp, url),
+	})
+	return Result{
 		URL:        url,
 		StatusCode: resp.StatusCode,
 		Status:     resp.Status,
 		Source:     SourceBing,
+		Class:      label.Class,
+		AuthScheme: label.Scheme,
 	}
 }
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/zvdy/parsero-go/internal/fingerprint"
+)
+
+// Technologies lists, sorted, every technology Options.Fingerprint can
+// recognise: the names Result.Fingerprints holds, such as "jenkins",
+// "phpmyadmin" or "wordpress". The set grows between releases.
+func Technologies() []string {
+	return fingerprint.Default().Names()
+}
+
+// iconCache memoizes favicon hashes per icon URL for the duration of one
+// CheckPaths call, since most paths on a host share the same icon. Each icon
+// is fetched once; workers wanting one in flight wait for it, others don't.
+type iconCache struct {
+	mu    sync.Mutex
+	icons map[string]*icon
+}
+
+type icon struct {
+	mu   sync.Mutex // held while fetching
+	done bool
+	hash *int32
+}
+
+func newIconCache() *iconCache {
+	return &iconCache{icons: map[string]*icon{}}
+}
+
+// fingerprint matches resp and its already-read body, plus the page's favicon if
+// it links one, against the signature database. ctx is the scan's, not the
+// probe's: the favicon is shared by every path, so one probe's deadline must
+// not decide it.
+func (s *Scanner) fingerprint(ctx context.Context, resp *http.Response, body []byte, icons *iconCache) []string {
+	fr := fingerprint.Response{Header: resp.Header, Body: body}
+
+	if href := fingerprint.IconHref(body); href != "" {
+		if u, err := resp.Request.URL.Parse(href); err == nil {
+			fr.Favicon = s.faviconHash(ctx, u.String(), icons)
+		}
+	}
+	return fingerprint.Default().Match(fr)
+}
+
+func (s *Scanner) faviconHash(ctx context.Context, url string, icons *iconCache) *int32 {
+	icons.mu.Lock()
+	ic, ok := icons.icons[url]
+	if !ok {
+		ic = &icon{}
+		icons.icons[url] = ic
+	}
+	icons.mu.Unlock()
+
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if !ic.done {
+		ic.hash = s.fetchFaviconHash(ctx, url)
+		// A fetch cut short by the scan ending says nothing about the icon;
+		// leave it for the next caller.
+		ic.done = ic.hash != nil || ctx.Err() == nil
+	}
+	return ic.hash
+}
+
+func (s *Scanner) fetchFaviconHash(ctx context.Context, url string) *int32 {
+	if s.opts.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.RequestTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("User-Agent", s.opts.UserAgent)
+	resp, err := s.do(TrafficProbe, req)
+	if err != nil {
+		return nil
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, s.opts.BodyLimit))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(data) == 0 {
+		return nil
+	}
+	h := fingerprint.FaviconHash(data)
+	return &h
+}
@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Traffic kinds, passed to request and response hooks.
+const (
+	TrafficRobots  = "robots"  // robots.txt fetches
+	TrafficProbe   = "probe"   // Disallow path probes, including body re-fetches and favicons
+	TrafficSearch  = "search"  // Bing queries and probes of the hits they return
+	TrafficArchive = "archive" // archive CDX queries and archived robots.txt fetches
+)
+
+// RequestHook runs on every outgoing request before it is sent and may
+// modify it: sign it, add a token, rewrite headers. A non-nil error cancels
+// the request, which then fails like a network error would.
+type RequestHook func(kind string, req *http.Request) error
+
+// Exchange is one finished request, as seen by a ResponseHook. Response is
+// nil when Err is set. Hooks must not read or close Response.Body; the
+// scanner still needs it.
+type Exchange struct {
+	Kind     string
+	Request  *http.Request
+	Response *http.Response
+	Err      error
+	Duration time.Duration
+}
+
+// ResponseHook observes every finished request, successful or not.
+type ResponseHook func(Exchange)
+
+// OnRequest adds a request hook. Hooks run in the order they were added, after
+// the scanner has set its own headers, and are called concurrently.
+func OnRequest(fn RequestHook) Option {
+	return func(s *Scanner) { s.onRequest = append(s.onRequest, fn) }
+}
+
+// OnResponse adds a response hook. Hooks run in the order they were added and
+// are called concurrently.
+func OnResponse(fn ResponseHook) Option {
+	return func(s *Scanner) { s.onResponse = append(s.onResponse, fn) }
+}
+
+type trafficKey struct{}
+
+// TrafficOf returns the traffic kind of a request the scanner sent, for
+// transports wrapped around its client (see pkg/har); "" for other requests.
+// Redirects keep the kind of the request that led to them.
+func TrafficOf(req *http.Request) string {
+	kind, _ := req.Context().Value(trafficKey{}).(string)
+	return kind
+}
+
+// do sends req through the hooks and the scanner's client. All of the
+// scanner's HTTP traffic goes through here.
+func (s *Scanner) do(kind string, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(context.WithValue(req.Context(), trafficKey{}, kind))
+	ex := Exchange{Kind: kind, Request: req}
+	for _, hook := range s.onRequest {
+		if err := hook(kind, req); err != nil {
+			ex.Err = fmt.Errorf("request hook: %w", err)
+			s.observe(ex)
+			return nil, ex.Err
+		}
+	}
+	start := time.Now()
+	ex.Response, ex.Err = s.client.Do(req)
+	ex.Duration = time.Since(start)
+	s.observe(ex)
+	return ex.Response, ex.Err
+}
+
+func (s *Scanner) observe(ex Exchange) {
+	for _, hook := range s.onResponse {
+		hook(ex)
+	}
+}
+
+// HostHeaders returns a request hook that sets per-host headers, e.g. a
+// token for one site only. Each spec is "host=Name: value"; host matches the
+// request's host name exactly, ignoring case and port, and the header
+// replaces any value already set.
+func HostHeaders(specs []string) (RequestHook, error) {
+	byHost := map[string]http.Header{}
+	for _, spec := range specs {
+		host, header, ok := strings.Cut(spec, "=")
+		name, value, ok2 := strings.Cut(header, ":")
+		host, name = strings.ToLower(strings.TrimSpace(host)), strings.TrimSpace(name)
+		if !ok || !ok2 || host == "" || name == "" {
+			return nil, fmt.Errorf("invalid host header %q (want 'host=Name: value')", spec)
+		}
+		if byHost[host] == nil {
+			byHost[host] = http.Header{}
+		}
+		byHost[host].Add(name, strings.TrimSpace(value))
+	}
+	return func(_ string, req *http.Request) error {
+		for name, values := range byHost[strings.ToLower(req.URL.Hostname())] {
+			req.Header[name] = values
+		}
+		return nil
+	}, nil
+}
+
+// HostHeaderNames lists the header names HostHeaders specs set, e.g. so a
+// recording can redact the tokens they carry.
+func HostHeaderNames(specs []string) []string {
+	var names []string
+	for _, spec := range specs {
+		_, header, _ := strings.Cut(spec, "=")
+		name, _, _ := strings.Cut(header, ":")
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
@@ -0,0 +1,294 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/classify"
+)
+
+var ErrNoRobots = fmt.Errorf("no robots.txt file has been found")
+
+// ErrRobotsUnreachable wraps the transport error of a robots.txt request that
+// failed outright (refused, reset, timed out), so it isn't mistaken for a
+// site without one.
+var ErrRobotsUnreachable = fmt.Errorf("robots.txt unreachable")
+
+// robotsLimit caps how much of a robots.txt is read; well past the 500 KiB
+// crawlers honour so the linter can still flag oversized files.
+const robotsLimit = 1 << 20
+
+// FetchRobots returns the raw body of {target}/robots.txt, up to 1 MiB.
+// A 404 yields ErrNoRobots and a transport error ErrRobotsUnreachable.
+func (s *Scanner) FetchRobots(ctx context.Context, target string) ([]byte, error) {
+	status, body, err := s.fetchRobots(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if s.onRobots != nil {
+		s.onRobots(status)
+	}
+	if status == http.StatusNotFound {
+		return nil, ErrNoRobots
+	}
+	return body, nil
+}
+
+func (s *Scanner) fetchRobots(ctx context.Context, target string) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.RobotsTimeout)
+	defer cancel()
+
+	url := BaseURL(target) + "/robots.txt"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("User-Agent", s.opts.UserAgent)
+
+	resp, err := s.do(TrafficRobots, req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %w", ErrRobotsUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, robotsLimit))
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %w", ErrRobotsUnreachable, err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// FetchDisallowPaths returns the Disallow paths from {target}/robots.txt
+// with the leading slash stripped, honoring ctx and the MaxPaths cap.
+func (s *Scanner) FetchDisallowPaths(ctx context.Context, target string) ([]string, error) {
+	if s.robotsCache != nil {
+		if paths, ok := s.robotsCache.GetRobots(ctx, target); ok {
+			return paths, nil
+		}
+	}
+
+	status, body, err := s.fetchRobots(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if s.onRobots != nil {
+		s.onRobots(status)
+	}
+	paths, err := s.ParseDisallowPaths(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing robots.txt isn't cached, so OnRobots sees every 404.
+	if s.robotsCache != nil && status != http.StatusNotFound {
+		s.robotsCache.SetRobots(ctx, target, paths, s.robotsTTL)
+	}
+	return paths, nil
+}
+
+// ParseDisallowPaths is FetchDisallowPaths for a robots.txt obtained some
+// other way, such as a copy from the site owner or an archive: it returns the
+// Disallow paths in data, parsed and capped the same way. Pass them to Resume
+// to probe them against a target.
+func (s *Scanner) ParseDisallowPaths(data []byte) ([]string, error) {
+	return parseDisallow(data, s.opts.MaxPaths)
+}
+
+// parseDisallow extracts "Disallow: /" entries with the leading slash
+// stripped, stopping after max paths when max > 0.
+func parseDisallow(data []byte, max int) ([]string, error) {
+	var paths []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "Disallow: /") {
+			path := strings.TrimSpace(strings.TrimPrefix(line, "Disallow: /"))
+			paths = append(paths, path)
+			if max > 0 && len(paths) >= max {
+				break
+			}
+		}
+	}
+	return paths, sc.Err()
+}
+
+// CheckPaths probes each path with a bounded worker pool — fixed, or adaptive
+// when Options.Adaptive is set; per-path errors are returned inside the
+// results.
+func (s *Scanner) CheckPaths(ctx context.Context, target string, paths []string) []Result {
+	jobs := make([]probeJob, len(paths))
+	for i, p := range paths {
+		jobs[i] = probeJob{path: p}
+	}
+	return s.checkPaths(ctx, target, jobs)
+}
+
+// probeJob is one path for checkPaths; archived is set for entries only
+// archived robots.txt versions list.
+type probeJob struct {
+	path     string
+	archived *ArchiveSpan
+}
+
+// checkPaths is CheckPaths for robots and archive entries alike, so both
+// share one pool, its concurrency control and its progress count.
+func (s *Scanner) checkPaths(ctx context.Context, target string, jobs []probeJob) []Result {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	work := make(chan probeJob, len(jobs))
+	out := make(chan timed, len(jobs))
+	icons := newIconCache()
+
+	workers := s.opts.Concurrency
+	var ctrl *aimd
+	if s.opts.Adaptive {
+		ctrl = newAIMD(s.opts.Concurrency, s.opts.MinConcurrency, s.opts.MaxConcurrency)
+		workers = s.opts.MaxConcurrency // idle until the controller admits them
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range work {
+				if ctrl != nil {
+					ctrl.acquire()
+				}
+				start := time.Now()
+				r := s.probe(ctx, target, j.path, icons)
+				elapsed := time.Since(start)
+				if ctrl != nil {
+					ctrl.release(elapsed, r)
+				}
+				if j.archived != nil {
+					r.Source, r.Archived = SourceArchive, j.archived
+				}
+				out <- timed{r, elapsed}
+			}
+		}()
+	}
+
+	go func() {
+		for _, j := range jobs {
+			work <- j
+		}
+		close(work)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	results := make([]Result, 0, len(jobs))
+	done := 0
+	for r := range out {
+		results = append(results, r.Result)
+		if s.onResult != nil {
+			s.onResult(r.Result, r.elapsed)
+		}
+		done++
+		if s.progress != nil {
+			s.progress(done, len(jobs))
+		}
+	}
+
+	if s.onProfile != nil {
+		if ctrl != nil {
+			s.onProfile(ctrl.profile())
+		} else {
+			s.onProfile(fixedProfile(workers))
+		}
+	}
+	return results
+}
+
+func (s *Scanner) probe(ctx context.Context, target, path string, icons *iconCache) Result {
+	disurl := BaseURL(target) + "/" + path
+
+	reqCtx := ctx
+	if s.opts.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, s.opts.RequestTimeout)
+		defer cancel()
+	}
+
+	doReq := func(method string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(reqCtx, method, disurl, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", s.opts.UserAgent)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9")
+		return s.do(TrafficProbe, req)
+	}
+
+	resp, err := doReq(http.MethodHead)
+	if err != nil {
+		// HEAD can be rejected by some servers; fall back to GET.
+		resp, err = doReq(http.MethodGet)
+		if err != nil {
+			return Result{URL: disurl, Error: err, Source: SourceRobots}
+		}
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	if s.opts.wantsBody() && resp.StatusCode != http.StatusNotFound {
+		// HEAD carries no body, so re-fetch with GET for inspection.
+		if resp.Request.Method == http.MethodHead {
+			if full, err := doReq(http.MethodGet); err == nil {
+				defer full.Body.Close()
+				resp = full
+			}
+		}
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, s.opts.BodyLimit))
+	}
+
+	res := Result{
+		URL:        disurl,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Source:     SourceRobots,
+	}
+	label := classify.Classify(classify.Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		URL:        redirectedTo(resp, disurl),
+	})
+	res.Class, res.AuthScheme = label.Class, label.Scheme
+	if s.opts.Fingerprint && resp.StatusCode != http.StatusNotFound {
+		res.Fingerprints = s.fingerprint(ctx, resp, body, icons)
+	}
+	if len(s.opts.Analyzers) > 0 && resp.StatusCode != http.StatusNotFound {
+		res.Findings = s.analyze(ctx, Response{
+			URL:        disurl,
+			FinalURL:   redirectedTo(resp, disurl),
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+			Class:      res.Class,
+		})
+	}
+	return res
+}
+
+// redirectedTo returns the final URL if the client followed a redirect away
+// from requested, else "".
+func redirectedTo(resp *http.Response, requested string) string {
+	if final := resp.Request.URL.String(); final != requested {
+		return final
+	}
+	return ""
+}
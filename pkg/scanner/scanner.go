@@ -0,0 +1,368 @@
+// Package scanner is parsero's robots.txt audit engine, for embedding in other
+// Go programs: it fetches a target's robots.txt, probes each Disallow path and
+// labels what it finds. It keeps no package-level state, writes nothing to
+// stdout, and sends every request through an injected *http.Client, so callers
+// can supply an SSRF-guarded transport for multi-tenant use.
+//
+//	s := scanner.New(scanner.WithConcurrency(8), scanner.WithFingerprint())
+//	results, disallow, err := s.Run(ctx, "example.com")
+//
+// # Compatibility
+//
+// The API is versioned by APIVersion and follows the module's semantic
+// versioning. Within a version, exported identifiers are not removed or
+// renamed and signatures don't change; Options only gains fields, whose zero
+// value keeps the earlier behaviour; new settings arrive as new Option
+// constructors; and Result only gains fields. Construct Options with field
+// names, not positionally. The probing heuristics (request methods, the
+// classifier, the fingerprint database) may improve between releases.
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/classify"
+	"github.com/zvdy/parsero-go/pkg/types"
+)
+
+// APIVersion is the major version of this package's API.
+const APIVersion = 1
+
+// Result is one probed path; ConcurrencyProfile summarises a scan's worker
+// pool. Both are shared with parsero's exports.
+type (
+	Result             = types.Result
+	ConcurrencyProfile = types.ConcurrencyProfile
+)
+
+// Result.Class values.
+const (
+	ClassOpen      = classify.Open      // content is served without authentication
+	ClassAuth      = classify.Auth      // HTTP auth challenge; see Result.AuthScheme
+	ClassLogin     = classify.Login     // a login form
+	ClassForbidden = classify.Forbidden // 403 without a recognisable block page
+	ClassBlocked   = classify.Blocked   // WAF, bot-protection or rate-limit block page
+	ClassNotFound  = classify.NotFound  // 404/410
+	ClassOther     = classify.Other     // anything else
+)
+
+const (
+	SourceRobots  = "robots"
+	SourceBing    = "bing"
+	SourceArchive = "archive"
+)
+
+// Options is the plain-struct form of a Scanner's settings, applied with
+// WithOptions. Zero fields take the documented defaults.
+type Options struct {
+	// Only200 is carried for callers that filter their reports; the scanner
+	// probes and returns every path regardless.
+	Only200     bool
+	SearchBing  bool
+	Concurrency int // default runtime.NumCPU()
+	MaxPaths    int // 0 = unlimited
+
+	// Adaptive starts at Concurrency and moves between MinConcurrency and
+	// MaxConcurrency (AIMD) based on latency, transport errors and 429/503s.
+	Adaptive       bool
+	MinConcurrency int // default 1
+	MaxConcurrency int // default 4 × Concurrency
+
+	// Fingerprint GETs every path that isn't a 404 and matches its headers,
+	// cookies, <meta> tags, body and favicon hash against an embedded signature
+	// set, naming what it recognises in Result.Fingerprints. Technologies
+	// lists every name it can report.
+	Fingerprint bool
+	// InspectBody GETs every path that isn't a 404 so the classifier can
+	// recognise login forms and block pages, not just status codes.
+	InspectBody bool
+	BodyLimit   int64 // max bytes read from a response body; default 64 KiB
+
+	// Analyzers run, in order, on every robots and archive probe that isn't a
+	// 404; they imply reading response bodies. See Analyzer.
+	Analyzers []AnalyzerConfig
+
+	// Archive also probes the Disallow entries of archived robots.txt
+	// versions that the live file no longer lists, each tagged with when it
+	// was listed. Versions come from the CDX API at ArchiveURL.
+	Archive         bool
+	ArchiveURL      string        // default DefaultArchiveURL
+	ArchiveVersions int           // caps distinct versions fetched, spread over the history; 0 = all (see OnArchive)
+	ArchiveTimeout  time.Duration // each archive request; default 15s
+
+	RobotsTimeout  time.Duration // robots.txt fetch; default 5s
+	RequestTimeout time.Duration // each probe; default 3s
+	UserAgent      string        // default DefaultUserAgent; Bing queries keep a browser UA
+}
+
+// DefaultUserAgent identifies parsero's robots.txt fetches and probes.
+const DefaultUserAgent = "Mozilla/5.0 Parsero/1.0"
+
+func (o Options) wantsBody() bool {
+	return o.Fingerprint || o.InspectBody || len(o.Analyzers) > 0
+}
+
+func (o Options) withDefaults() Options {
+	if o.Concurrency <= 0 {
+		o.Concurrency = runtime.NumCPU()
+	}
+	if o.MinConcurrency <= 0 {
+		o.MinConcurrency = 1
+	}
+	if o.MaxConcurrency <= 0 {
+		o.MaxConcurrency = 4 * o.Concurrency
+	}
+	if o.MaxConcurrency < o.MinConcurrency {
+		o.MaxConcurrency = o.MinConcurrency
+	}
+	if o.RobotsTimeout <= 0 {
+		o.RobotsTimeout = 5 * time.Second
+	}
+	if o.RequestTimeout <= 0 {
+		o.RequestTimeout = 3 * time.Second
+	}
+	if o.BodyLimit <= 0 {
+		o.BodyLimit = 64 << 10
+	}
+	if o.UserAgent == "" {
+		o.UserAgent = DefaultUserAgent
+	}
+	if o.ArchiveURL == "" {
+		o.ArchiveURL = DefaultArchiveURL
+	}
+	if o.ArchiveTimeout <= 0 {
+		o.ArchiveTimeout = 15 * time.Second
+	}
+	return o
+}
+
+// RobotsCache lets bursts of scans on the same target skip the robots fetch.
+// Implementations must be safe for concurrent use; a nil cache disables caching.
+type RobotsCache interface {
+	GetRobots(ctx context.Context, target string) ([]string, bool)
+	SetRobots(ctx context.Context, target string, paths []string, ttl time.Duration)
+}
+
+// Scanner carries no per-scan state and is safe to reuse across scans, and
+// for concurrent scans when its callbacks are.
+type Scanner struct {
+	client      *http.Client
+	opts        Options
+	progress    func(done, total int)
+	onProfile   func(ConcurrencyProfile)
+	onResult    func(Result, time.Duration)
+	onRobots    func(statusCode int)
+	onArchive   func(read, total int)
+	robotsCache RobotsCache
+	robotsTTL   time.Duration
+
+	onAnalyzerError func(analyzer, url string, err error)
+	onRequest       []RequestHook
+	onResponse      []ResponseHook
+}
+
+// Option configures a Scanner.
+type Option func(*Scanner)
+
+// New builds a Scanner from opts, applied in order.
+func New(opts ...Option) *Scanner {
+	s := &Scanner{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.client == nil {
+		s.client = &http.Client{}
+	}
+	s.opts = s.opts.withDefaults()
+	return s
+}
+
+// WithHTTPClient sends every request through c; its Transport is where
+// callers wanting SSRF protection inject a guarded dialer.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Scanner) { s.client = c }
+}
+
+// WithOptions replaces all settings with o, including any made by earlier
+// options, so pass it first.
+func WithOptions(o Options) Option {
+	return func(s *Scanner) { s.opts = o }
+}
+
+// WithConcurrency sets the number of probe workers.
+func WithConcurrency(n int) Option {
+	return func(s *Scanner) { s.opts.Concurrency = n }
+}
+
+// WithAdaptive moves the worker count between min and max (AIMD) based on
+// latency, transport errors and 429/503s, starting at the concurrency. Zero
+// bounds take their defaults.
+func WithAdaptive(min, max int) Option {
+	return func(s *Scanner) {
+		s.opts.Adaptive, s.opts.MinConcurrency, s.opts.MaxConcurrency = true, min, max
+	}
+}
+
+// WithMaxPaths probes at most n Disallow entries.
+func WithMaxPaths(n int) Option {
+	return func(s *Scanner) { s.opts.MaxPaths = n }
+}
+
+// WithBing also probes paths Bing has indexed under each Disallow entry.
+func WithBing() Option {
+	return func(s *Scanner) { s.opts.SearchBing = true }
+}
+
+// WithArchive also probes Disallow entries that only archived robots.txt
+// versions list, found through the CDX API at baseURL ("" for
+// DefaultArchiveURL).
+func WithArchive(baseURL string) Option {
+	return func(s *Scanner) { s.opts.Archive, s.opts.ArchiveURL = true, baseURL }
+}
+
+// WithFingerprint matches every path that isn't a 404 against the embedded
+// technology signatures.
+func WithFingerprint() Option {
+	return func(s *Scanner) { s.opts.Fingerprint = true }
+}
+
+// WithInspectBody reads response bodies so login forms and block pages are
+// recognised, not just status codes.
+func WithInspectBody() Option {
+	return func(s *Scanner) { s.opts.InspectBody = true }
+}
+
+// WithBodyLimit caps the bytes read from each response body.
+func WithBodyLimit(n int64) Option {
+	return func(s *Scanner) { s.opts.BodyLimit = n }
+}
+
+// WithTimeouts bounds the robots.txt fetch and each probe; zero keeps the
+// default.
+func WithTimeouts(robots, request time.Duration) Option {
+	return func(s *Scanner) { s.opts.RobotsTimeout, s.opts.RequestTimeout = robots, request }
+}
+
+// WithUserAgent sets the User-Agent of robots.txt fetches and probes.
+func WithUserAgent(ua string) Option {
+	return func(s *Scanner) { s.opts.UserAgent = ua }
+}
+
+// WithRobotsCache lets bursts of scans on the same target skip the robots
+// fetch, keeping Disallow lists for ttl.
+func WithRobotsCache(c RobotsCache, ttl time.Duration) Option {
+	return func(s *Scanner) { s.robotsCache, s.robotsTTL = c, ttl }
+}
+
+// OnProgress receives the count of probed paths after each probe.
+func OnProgress(fn func(done, total int)) Option {
+	return func(s *Scanner) { s.progress = fn }
+}
+
+// OnConcurrency receives the concurrency profile at the end of each CheckPaths
+// call, for recording in scan metadata.
+func OnConcurrency(fn func(ConcurrencyProfile)) Option {
+	return func(s *Scanner) { s.onProfile = fn }
+}
+
+// OnResult receives each probe result as it completes, with the time the probe
+// took, before the scan finishes. Calls are serialised, so fn needn't lock.
+func OnResult(fn func(Result, time.Duration)) Option {
+	return func(s *Scanner) { s.onResult = fn }
+}
+
+// OnRobots receives the HTTP status of each robots.txt fetch, so callers can
+// tell a missing file (404) from one with no Disallow entries. Cache hits,
+// which are never a 404, don't call it.
+func OnRobots(fn func(statusCode int)) Option {
+	return func(s *Scanner) { s.onRobots = fn }
+}
+
+// OnArchive receives, after each archive lookup, how many distinct archived
+// robots.txt versions were read out of the total the archive holds. Fewer
+// means entries are missing, and spans may be short: ArchiveVersions capped
+// them, or snapshots failed to load.
+func OnArchive(fn func(read, total int)) Option {
+	return func(s *Scanner) { s.onArchive = fn }
+}
+
+// timed pairs a result with how long its probe took.
+type timed struct {
+	Result
+	elapsed time.Duration
+}
+
+// BaseURL is target as a URL prefix. Targets may carry a scheme and port
+// ("https://example.com:8443"); bare hosts are plain http.
+func BaseURL(target string) string {
+	if strings.Contains(target, "://") {
+		return strings.TrimSuffix(target, "/")
+	}
+	return "http://" + target
+}
+
+// Run fetches robots.txt, probes each disallow path, and optionally augments with
+// Bing and the archive. err is non-nil only for fatal failures (e.g. no
+// robots.txt); per-path errors live in the results slice. With the archive
+// enabled, a robots.txt that can't be fetched is searched for there too, and
+// any archived entries found take the place of ErrRobotsUnreachable.
+func (s *Scanner) Run(ctx context.Context, target string) (results []Result, disallow []string, err error) {
+	disallow, err = s.FetchDisallowPaths(ctx, target)
+	if errors.Is(err, ErrRobotsUnreachable) && s.opts.Archive {
+		if results = s.Resume(ctx, target, nil, nil); len(results) > 0 {
+			return results, nil, nil
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(disallow) == 0 {
+		if s.opts.Archive {
+			// Entries removed since are exactly what the archive is for.
+			return s.Resume(ctx, target, nil, nil), nil, nil
+		}
+		return nil, nil, nil
+	}
+	return s.Resume(ctx, target, disallow, nil), disallow, nil
+}
+
+// Resume is the probing half of Run for a Disallow list fetched earlier or
+// read with ParseDisallowPaths: it skips paths whose URL is in probed, so a
+// checkpointed scan picks up where it stopped and covers the same list. Bing
+// and the archive, when enabled, are searched again in full.
+func (s *Scanner) Resume(ctx context.Context, target string, disallow []string, probed map[string]bool) []Result {
+	paths := disallow
+	if len(probed) > 0 {
+		paths = make([]string, 0, len(disallow))
+		for _, p := range disallow {
+			if !probed[BaseURL(target)+"/"+p] {
+				paths = append(paths, p)
+			}
+		}
+	}
+	jobs := make([]probeJob, len(paths))
+	for i, p := range paths {
+		jobs[i] = probeJob{path: p}
+	}
+	if s.opts.Archive {
+		// MaxPaths caps the probes of both sources together.
+		room := 0 // unlimited
+		if s.opts.MaxPaths > 0 {
+			room = s.opts.MaxPaths - len(disallow)
+		}
+		if s.opts.MaxPaths == 0 || room > 0 {
+			jobs = append(jobs, s.archiveJobs(ctx, target, disallow, room)...)
+		}
+	}
+	results := s.checkPaths(ctx, target, jobs)
+
+	if s.opts.SearchBing {
+		results = append(results, s.searchBing(ctx, target, disallow)...)
+	}
+	return results
+}
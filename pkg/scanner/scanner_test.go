@@ -0,0 +1,794 @@
+package scanner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zvdy/parsero-go/pkg/scanner"
+)
+
+// newRobotsServer serves a robots.txt with three disallow entries and canned
+// statuses for each path.
+func newRobotsServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("User-agent: *\nDisallow: /admin/\nDisallow: /private/\nDisallow: /secret.html\nAllow: /public/\n"))
+		case "/admin/":
+			w.WriteHeader(http.StatusForbidden)
+		case "/private/":
+			w.WriteHeader(http.StatusNotFound)
+		case "/secret.html":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFetchDisallowPaths(t *testing.T) {
+	srv := newRobotsServer()
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(1))
+	paths, err := s.FetchDisallowPaths(context.Background(), target)
+	if err != nil {
+		t.Fatalf("FetchDisallowPaths: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected 3 paths, got %d: %v", len(paths), paths)
+	}
+	want := map[string]bool{"admin/": true, "private/": true, "secret.html": true}
+	for _, p := range paths {
+		if !want[p] {
+			t.Errorf("unexpected path: %q", p)
+		}
+	}
+}
+
+func TestFetchDisallowPathsMaxCap(t *testing.T) {
+	srv := newRobotsServer()
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithOptions(scanner.Options{Concurrency: 1, MaxPaths: 2}))
+	paths, err := s.FetchDisallowPaths(context.Background(), target)
+	if err != nil {
+		t.Fatalf("FetchDisallowPaths: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("MaxPaths cap not honored: got %d paths", len(paths))
+	}
+}
+
+func TestParseDisallowPaths(t *testing.T) {
+	s := scanner.New(scanner.WithMaxPaths(2))
+	paths, err := s.ParseDisallowPaths([]byte("User-agent: *\nAllow: /public/\nDisallow: /a/\nDisallow: /b/\nDisallow: /c/\n"))
+	if err != nil {
+		t.Fatalf("ParseDisallowPaths: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "a/" || paths[1] != "b/" {
+		t.Errorf("got %v, want the first two entries", paths)
+	}
+}
+
+func TestRunReturnsResults(t *testing.T) {
+	srv := newRobotsServer()
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(2))
+	results, disallow, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(disallow) != 3 {
+		t.Fatalf("expected 3 disallow paths, got %d", len(disallow))
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	codes := map[int]int{}
+	for _, r := range results {
+		if r.Error != nil {
+			t.Errorf("unexpected error for %s: %v", r.URL, r.Error)
+		}
+		if r.Source != scanner.SourceRobots {
+			t.Errorf("expected source %q, got %q", scanner.SourceRobots, r.Source)
+		}
+		codes[r.StatusCode]++
+	}
+	if codes[200] != 1 || codes[403] != 1 || codes[404] != 1 {
+		t.Errorf("unexpected status distribution: %v", codes)
+	}
+}
+
+func TestRunTargetWithScheme(t *testing.T) {
+	plain := newRobotsServer()
+	plain.Close() // only its handler is needed
+	srv := httptest.NewTLSServer(plain.Config.Handler)
+	defer srv.Close()
+
+	// srv.URL is "https://127.0.0.1:port": scheme and port must be kept.
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(2))
+	results, disallow, err := s.Run(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(disallow) != 3 || len(results) != 3 {
+		t.Fatalf("got %d disallow, %d results", len(disallow), len(results))
+	}
+	for _, r := range results {
+		if !strings.HasPrefix(r.URL, srv.URL+"/") {
+			t.Errorf("result URL %q doesn't keep the target's scheme and port", r.URL)
+		}
+	}
+}
+
+func TestRunNoRobots(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	// 404 robots.txt still returns a (empty) body, so no fatal error but no paths.
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(1))
+	results, disallow, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(disallow) != 0 || len(results) != 0 {
+		t.Fatalf("expected no paths/results, got %d/%d", len(disallow), len(results))
+	}
+}
+
+// mapCache is an in-memory RobotsCache.
+type mapCache struct {
+	mu    sync.Mutex
+	paths map[string][]string
+}
+
+func (c *mapCache) GetRobots(_ context.Context, target string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.paths[target]
+	return p, ok
+}
+
+func (c *mapCache) SetRobots(_ context.Context, target string, paths []string, _ time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths[target] = paths
+}
+
+// A missing robots.txt is fetched again on every scan, so OnRobots can
+// report the 404 each time.
+func TestRobotsCacheSkipsMissing(t *testing.T) {
+	found, missing := newRobotsServer(), httptest.NewServer(http.NotFoundHandler())
+	defer found.Close()
+	defer missing.Close()
+
+	cache := &mapCache{paths: map[string][]string{}}
+	for _, tt := range []struct {
+		srv  *httptest.Server
+		want []int
+	}{
+		{found, []int{200}},
+		{missing, []int{404, 404}},
+	} {
+		var got []int
+		s := scanner.New(scanner.WithHTTPClient(tt.srv.Client()), scanner.WithRobotsCache(cache, time.Minute),
+			scanner.OnRobots(func(status int) { got = append(got, status) }))
+		target := strings.TrimPrefix(tt.srv.URL, "http://")
+		for range 2 {
+			if _, err := s.FetchDisallowPaths(context.Background(), target); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: OnRobots saw %v, want %v", target, got, tt.want)
+		}
+	}
+}
+
+func TestRobotsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	// WithOptions goes first; the options after it adjust its settings.
+	s := scanner.New(
+		scanner.WithOptions(scanner.Options{RobotsTimeout: time.Hour}),
+		scanner.WithHTTPClient(srv.Client()),
+		scanner.WithTimeouts(50*time.Millisecond, 0),
+	)
+	start := time.Now()
+	if _, _, err := s.Run(context.Background(), target); !errors.Is(err, scanner.ErrRobotsUnreachable) {
+		t.Fatalf("Run error = %v, want ErrRobotsUnreachable", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("robots fetch took %v despite a 50ms timeout", elapsed)
+	}
+}
+
+func TestResumeSkipsProbed(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	robots := newRobotsServer()
+	defer robots.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		robots.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(2))
+	disallow := []string{"admin/", "private/", "secret.html"}
+	probed := map[string]bool{srv.URL + "/admin/": true}
+	results := s.Resume(context.Background(), target, disallow, probed)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if hits["/admin/"] != 0 || hits["/private/"] != 1 || hits["/secret.html"] != 1 || hits["/robots.txt"] != 0 {
+		t.Errorf("unexpected requests: %v", hits)
+	}
+}
+
+func TestProgressCallback(t *testing.T) {
+	srv := newRobotsServer()
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	var lastDone, lastTotal int
+	s := scanner.New(
+		scanner.WithHTTPClient(srv.Client()),
+		scanner.WithConcurrency(1),
+		scanner.OnProgress(func(done, total int) { lastDone, lastTotal = done, total }),
+	)
+	paths, _ := s.FetchDisallowPaths(context.Background(), target)
+	s.CheckPaths(context.Background(), target, paths)
+
+	if lastTotal != len(paths) || lastDone != len(paths) {
+		t.Errorf("progress ended at %d/%d, want %d/%d", lastDone, lastTotal, len(paths), len(paths))
+	}
+}
+
+func TestResultCallback(t *testing.T) {
+	srv := newRobotsServer()
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	var streamed []string
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(2),
+		scanner.OnResult(func(r scanner.Result, elapsed time.Duration) {
+			if elapsed <= 0 {
+				t.Errorf("%s: non-positive elapsed %v", r.URL, elapsed)
+			}
+			streamed = append(streamed, r.URL)
+		}))
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(streamed) != len(results) {
+		t.Errorf("streamed %d results, Run returned %d", len(streamed), len(results))
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /ci/\nDisallow: /gone/\n"))
+		case "/ci/":
+			w.Header().Set("X-Jenkins", "2.440")
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(1), scanner.WithFingerprint())
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, r := range results {
+		switch {
+		case strings.HasSuffix(r.URL, "/ci/"):
+			if len(r.Fingerprints) != 1 || r.Fingerprints[0] != "jenkins" {
+				t.Errorf("ci fingerprints = %v, want [jenkins]", r.Fingerprints)
+			}
+		case len(r.Fingerprints) != 0:
+			t.Errorf("%s: unexpected fingerprints %v", r.URL, r.Fingerprints)
+		}
+	}
+	if !slices.Contains(scanner.Technologies(), "jenkins") {
+		t.Errorf("Technologies() = %v, missing jenkins", scanner.Technologies())
+	}
+}
+
+func TestFaviconFetchedOnceNotSerialised(t *testing.T) {
+	var mu sync.Mutex
+	fetches := map[string]int{}
+	both := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /a1/\nDisallow: /a2/\nDisallow: /b1/\nDisallow: /b2/\n"))
+		case strings.HasSuffix(r.URL.Path, ".ico"):
+			mu.Lock()
+			fetches[r.URL.Path]++
+			if len(fetches) == 2 && fetches[r.URL.Path] == 1 {
+				close(both)
+			}
+			mu.Unlock()
+			// Each icon waits for the other: fetching them one at a time
+			// would stall here.
+			select {
+			case <-both:
+				w.Write([]byte("icon"))
+			case <-time.After(2 * time.Second):
+				w.WriteHeader(http.StatusGatewayTimeout)
+			}
+		default:
+			fmt.Fprintf(w, `<link rel="icon" href="/%c.ico">`, r.URL.Path[1])
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(4), scanner.WithFingerprint())
+	start := time.Now()
+	if _, _, err := s.Run(context.Background(), target); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("favicon fetches were serialised: scan took %v", elapsed)
+	}
+	if fetches["/a.ico"] != 1 || fetches["/b.ico"] != 1 {
+		t.Errorf("favicon fetches %v, want each icon once", fetches)
+	}
+}
+
+// The favicon fetch gets its own deadline, not what is left of the probe
+// that found it.
+func TestFaviconOutlivesProbeDeadline(t *testing.T) {
+	served := make(chan bool, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /slow/\n"))
+		case r.URL.Path == "/favicon.ico":
+			select {
+			case <-time.After(150 * time.Millisecond):
+				w.Write([]byte("icon"))
+				served <- true
+			case <-r.Context().Done():
+				served <- false
+			}
+		default:
+			if r.Method == http.MethodGet {
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.Write([]byte(`<link rel="icon" href="/favicon.ico">`))
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithFingerprint(),
+		scanner.WithTimeouts(0, 300*time.Millisecond))
+	if _, _, err := s.Run(context.Background(), target); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	select {
+	case ok := <-served:
+		if !ok {
+			t.Error("favicon fetch was cut off by the probe's deadline")
+		}
+	case <-time.After(time.Second):
+		t.Error("favicon was never fetched")
+	}
+}
+
+func TestClassification(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /admin/\nDisallow: /manager/\nDisallow: /docs/\n"))
+		case "/admin/":
+			w.Write([]byte(`<form method="post"><input type="password" name="pw"></form>`))
+		case "/manager/":
+			w.Header().Set("WWW-Authenticate", `Basic realm="Tomcat Manager Application"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/docs/":
+			w.Write([]byte("<h1>docs</h1>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(1), scanner.WithInspectBody())
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := map[string]string{}
+	for _, r := range results {
+		got[strings.TrimPrefix(r.URL, srv.URL)] = r.Class + "|" + r.AuthScheme
+	}
+	want := map[string]string{"/admin/": "login|", "/manager/": "auth|Basic", "/docs/": "open|"}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s classified %q, want %q", path, got[path], w)
+		}
+	}
+}
+
+func TestAdaptiveProfile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /a\nDisallow: /b\nDisallow: /c\nDisallow: /d\n"))
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	var prof scanner.ConcurrencyProfile
+	s := scanner.New(scanner.WithHTTPClient(srv.Client()), scanner.WithConcurrency(4), scanner.WithAdaptive(0, 8),
+		scanner.OnConcurrency(func(p scanner.ConcurrencyProfile) { prof = p }))
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	if prof.Mode != "adaptive" || prof.Initial != 4 || prof.Max != 8 {
+		t.Errorf("unexpected profile: %+v", prof)
+	}
+	if prof.Decreases == 0 || prof.Final >= prof.Initial {
+		t.Errorf("429s should have backed concurrency off: %+v", prof)
+	}
+}
+
+// analyzerFunc adapts a function to scanner.Analyzer.
+type analyzerFunc struct {
+	name string
+	fn   func(context.Context, scanner.Response) ([]scanner.Finding, error)
+}
+
+func (a analyzerFunc) Name() string { return a.name }
+func (a analyzerFunc) Analyze(ctx context.Context, r scanner.Response) ([]scanner.Finding, error) {
+	return a.fn(ctx, r)
+}
+
+func TestAnalyzersIsolated(t *testing.T) {
+	srv := newRobotsServer()
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	tagger := analyzerFunc{"tagger", func(_ context.Context, r scanner.Response) ([]scanner.Finding, error) {
+		r.Header.Set("X-Mutated", "1") // must not leak into the next analyzer
+		return []scanner.Finding{{Message: "status " + r.Class, Severity: scanner.SeverityNote}}, nil
+	}}
+	panicky := analyzerFunc{"panicky", func(context.Context, scanner.Response) ([]scanner.Finding, error) {
+		panic("boom")
+	}}
+	slow := analyzerFunc{"slow", func(ctx context.Context, _ scanner.Response) ([]scanner.Finding, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	checker := analyzerFunc{"checker", func(_ context.Context, r scanner.Response) ([]scanner.Finding, error) {
+		if r.Header.Get("X-Mutated") != "" {
+			return nil, errors.New("saw another analyzer's header change")
+		}
+		return nil, nil
+	}}
+
+	var mu sync.Mutex
+	failures := map[string]int{}
+	s := scanner.New(
+		scanner.WithHTTPClient(srv.Client()),
+		scanner.WithAnalyzer(tagger, 0),
+		scanner.WithAnalyzer(panicky, 0),
+		scanner.WithAnalyzer(slow, 50*time.Millisecond),
+		scanner.WithAnalyzer(checker, 0),
+		scanner.OnAnalyzerError(func(name, _ string, err error) {
+			mu.Lock()
+			failures[name]++
+			mu.Unlock()
+		}),
+	)
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, r := range results {
+		want := 1
+		if r.StatusCode == http.StatusNotFound {
+			want = 0
+		}
+		if len(r.Findings) != want {
+			t.Errorf("%s: findings %+v, want %d", r.URL, r.Findings, want)
+			continue
+		}
+		if want == 1 && (r.Findings[0].Analyzer != "tagger" || !strings.HasPrefix(r.Findings[0].Message, "status ")) {
+			t.Errorf("%s: finding %+v", r.URL, r.Findings[0])
+		}
+	}
+	// /admin/ and /secret.html are analyzed; /private/ is a 404.
+	if failures["panicky"] != 2 || failures["slow"] != 2 || failures["checker"] != 0 || failures["tagger"] != 0 {
+		t.Errorf("analyzer failures = %v", failures)
+	}
+}
+
+func TestRequestResponseHooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "t0k" || r.Header.Get("User-Agent") != "signed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /a/\nDisallow: /b/\n"))
+		case "/a/":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	tokens, err := scanner.HostHeaders([]string{"127.0.0.1=X-Token: t0k", "example.com=X-Token: other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	seen := map[string][]int{}
+	s := scanner.New(
+		scanner.WithHTTPClient(srv.Client()),
+		scanner.WithConcurrency(1),
+		scanner.OnRequest(tokens),
+		scanner.OnRequest(func(_ string, req *http.Request) error {
+			req.Header.Set("User-Agent", "signed") // overrides the scanner's own
+			return nil
+		}),
+		scanner.OnResponse(func(ex scanner.Exchange) {
+			mu.Lock()
+			defer mu.Unlock()
+			if ex.Err != nil {
+				t.Errorf("%s %s: %v", ex.Kind, ex.Request.URL, ex.Err)
+				return
+			}
+			seen[ex.Kind] = append(seen[ex.Kind], ex.Response.StatusCode)
+		}),
+	)
+	results, _, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if got := seen[scanner.TrafficRobots]; len(got) != 1 || got[0] != 200 {
+		t.Errorf("robots exchanges %v, want [200]", got)
+	}
+	if got := seen[scanner.TrafficProbe]; len(got) != 2 {
+		t.Errorf("probe exchanges %v, want 2", got)
+	}
+
+	// A failing request hook fails the request, not the process.
+	veto := scanner.New(
+		scanner.WithHTTPClient(srv.Client()),
+		scanner.OnRequest(func(kind string, _ *http.Request) error {
+			return errors.New("no " + kind)
+		}),
+	)
+	if _, _, err := veto.Run(context.Background(), target); err == nil || !strings.Contains(err.Error(), "no robots") {
+		t.Errorf("vetoed Run error = %v", err)
+	}
+
+	if _, err := scanner.HostHeaders([]string{"X-Token: t0k"}); err == nil {
+		t.Error("HostHeaders accepted a spec without a host")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	var mu sync.Mutex
+	kinds := map[string]int{}
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /current/\n"))
+		case "/old/":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer site.Close()
+	target := strings.TrimPrefix(site.URL, "http://")
+
+	versions := map[string]string{
+		"20150101000000": "User-agent: *\nDisallow: /current/\nDisallow: /old/\n",
+		"20170101000000": "User-agent: *\nDisallow: /current/\nDisallow: /mid/\n",
+	}
+	var snapshots []string
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cdx/search/cdx" {
+			if r.URL.Query().Get("url") != target+"/robots.txt" {
+				t.Errorf("CDX query for %q", r.URL.Query().Get("url"))
+			}
+			orig := site.URL + "/robots.txt"
+			// /old/ is listed, dropped in 2017, and restored in 2018.
+			fmt.Fprintf(w, `[["timestamp","original","digest"],
+				["20150101000000",%[1]q,"A"],["20160101000000",%[1]q,"A"],
+				["20170101000000",%[1]q,"B"],["20180101000000",%[1]q,"A"],["20190601000000",%[1]q,"A"]]`, orig)
+			return
+		}
+		ts, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/web/"), "id_/")
+		mu.Lock()
+		snapshots = append(snapshots, ts)
+		mu.Unlock()
+		w.Write([]byte(versions[ts]))
+	}))
+	defer archive.Close()
+
+	var progress [][2]int
+	profiles := 0
+	s := scanner.New(
+		scanner.WithHTTPClient(site.Client()),
+		scanner.WithConcurrency(2),
+		scanner.WithArchive(archive.URL),
+		scanner.OnRequest(func(kind string, _ *http.Request) error {
+			mu.Lock()
+			kinds[kind]++
+			mu.Unlock()
+			return nil
+		}),
+		scanner.OnProgress(func(done, total int) { progress = append(progress, [2]int{done, total}) }),
+		scanner.OnConcurrency(func(scanner.ConcurrencyProfile) { profiles++ }),
+	)
+	results, disallow, err := s.Run(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(disallow) != 1 || len(results) != 3 {
+		t.Fatalf("got %d results for disallow %v, want the live entry plus two archived ones", len(results), disallow)
+	}
+	// Archived entries go through the same pool as the live ones.
+	if len(progress) != 3 || progress[2] != [2]int{3, 3} || profiles != 1 {
+		t.Errorf("progress %v, %d concurrency profiles; want one pool of 3 probes", progress, profiles)
+	}
+	day := func(s string) time.Time { t, _ := time.Parse("2006-01-02", s); return t }
+	want := map[string]scanner.ArchiveSpan{
+		"/old/": {First: day("2015-01-01"), Last: day("2019-06-01")},
+		"/mid/": {First: day("2017-01-01"), Last: day("2017-01-01")},
+	}
+	for _, r := range results {
+		path := strings.TrimPrefix(r.URL, site.URL)
+		span, archived := want[path]
+		switch {
+		case !archived && (r.Source != scanner.SourceRobots || r.Archived != nil):
+			t.Errorf("%s: source %q, archived %v", path, r.Source, r.Archived)
+		case archived && (r.Source != scanner.SourceArchive || r.Archived == nil || *r.Archived != span):
+			t.Errorf("%s: source %q, archived %+v, want %+v", path, r.Source, r.Archived, span)
+		}
+		if path == "/old/" && r.StatusCode != http.StatusOK {
+			t.Errorf("/old/ probed as %d", r.StatusCode)
+		}
+	}
+	// Each distinct version is fetched once, even when it recurs.
+	if len(snapshots) != 2 || kinds[scanner.TrafficArchive] != 3 {
+		t.Errorf("snapshots %v, %d archive requests", snapshots, kinds[scanner.TrafficArchive])
+	}
+
+	// MaxPaths caps live and archived probes together, keeping the oldest.
+	capped := scanner.New(scanner.WithHTTPClient(site.Client()), scanner.WithArchive(archive.URL), scanner.WithMaxPaths(2))
+	results, _, err = capped.Run(context.Background(), target)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("MaxPaths 2: %d results, err %v", len(results), err)
+	}
+	for _, r := range results {
+		if strings.HasSuffix(r.URL, "/mid/") {
+			t.Errorf("MaxPaths kept the newer archived entry %s", r.URL)
+		}
+	}
+
+	// With robots.txt unreachable, the archive still supplies entries.
+	down := scanner.New(
+		scanner.WithHTTPClient(site.Client()),
+		scanner.WithArchive(archive.URL),
+		scanner.OnRequest(func(kind string, _ *http.Request) error {
+			if kind == scanner.TrafficRobots {
+				return errors.New("down")
+			}
+			return nil
+		}),
+	)
+	results, disallow, err = down.Run(context.Background(), target)
+	if err != nil || disallow != nil || len(results) != 3 {
+		t.Errorf("robots.txt down: %d results for disallow %v, err %v; want all three archived entries", len(results), disallow, err)
+	}
+
+	// An unreachable archive adds nothing and fails nothing.
+	archive.Close()
+	if _, _, err := down.Run(context.Background(), target); !errors.Is(err, scanner.ErrRobotsUnreachable) {
+		t.Errorf("robots.txt and archive down: err %v, want ErrRobotsUnreachable", err)
+	}
+	results, _, err = s.Run(context.Background(), target)
+	if err != nil || len(results) != 1 {
+		t.Errorf("with the archive down: %d results, err %v", len(results), err)
+	}
+}
+
+func TestArchiveEveryVersion(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer site.Close()
+	target := strings.TrimPrefix(site.URL, "http://")
+
+	// 30 revisions, each listing an entry of its own.
+	const n = 30
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cdx/search/cdx" {
+			rows := []string{`["timestamp","original","digest"]`}
+			for i := range n {
+				rows = append(rows, fmt.Sprintf(`["%d0101000000","%s/robots.txt","D%d"]`, 1990+i, site.URL, i))
+			}
+			fmt.Fprint(w, "["+strings.Join(rows, ",")+"]")
+			return
+		}
+		ts, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/web/"), "id_/")
+		fmt.Fprintf(w, "User-agent: *\nDisallow: /v%s/\n", ts[:4])
+	}))
+	defer archive.Close()
+
+	for _, tt := range []struct{ cap, want int }{{0, n}, {5, 5}} {
+		var read, total int
+		s := scanner.New(
+			scanner.WithOptions(scanner.Options{Archive: true, ArchiveURL: archive.URL, ArchiveVersions: tt.cap}),
+			scanner.WithHTTPClient(site.Client()),
+			scanner.OnArchive(func(r, t int) { read, total = r, t }),
+		)
+		results, _, err := s.Run(context.Background(), target)
+		if err != nil || len(results) != tt.want || read != tt.want || total != n {
+			t.Errorf("cap %d: %d results, read %d of %d versions, err %v; want %d of %d",
+				tt.cap, len(results), read, total, err, tt.want, n)
+		}
+	}
+}
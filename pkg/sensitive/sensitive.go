@@ -0,0 +1,30 @@
+// Package sensitive recognises path names that obviously point at something
+// worth protecting — admin consoles, VCS metadata, backups, credentials. It is
+// shared by the SARIF severity, the robots.txt linter and report formats.
+package sensitive
+
+import "strings"
+
+// Markers are matched case-insensitively as substrings of a path or URL.
+var Markers = []string{
+	"admin", "login", "wp-admin", "phpmyadmin", ".git", ".env", "backup",
+	"config", "secret", "private", "password", "db", "sql", "dump", ".ssh",
+	"credential", "token", "api-key", "apikey", "internal",
+}
+
+// Match returns the first marker found in s.
+func Match(s string) (string, bool) {
+	lower := strings.ToLower(s)
+	for _, m := range Markers {
+		if strings.Contains(lower, m) {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// Is reports whether s contains any marker.
+func Is(s string) bool {
+	_, ok := Match(s)
+	return ok
+}
@@ -1,7 +1,12 @@
 // Package types contains shared type definitions and constants used across the application
 package types
 
-import "runtime"
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"time"
+)
 
 // DefaultConcurrency is the default number of concurrent workers
 // It uses the number of available CPU cores
@@ -11,6 +16,40 @@ var DefaultConcurrency = runtime.NumCPU()
 // to prevent rate limiting
 var HalfDefaultConcurrency = runtime.NumCPU() / 2
 
+// ConcurrencyProfile summarises how a scan used its worker pool: a fixed
+// count, or the range an adaptive controller moved through.
+type ConcurrencyProfile struct {
+	Mode      string  `json:"mode"` // "fixed" or "adaptive"
+	Min       int     `json:"min"`
+	Max       int     `json:"max"`
+	Initial   int     `json:"initial"`
+	Final     int     `json:"final"`
+	Peak      int     `json:"peak"`
+	Mean      float64 `json:"mean"` // average limit a probe was admitted under
+	Increases int     `json:"increases"`
+	Decreases int     `json:"decreases"`
+}
+
+// PolicyDecision is the --fail-on verdict for one target. Outcome is "pass",
+// "findings", "scan_error" or "no_robots"; ExitCode is what the CLI exits
+// with for it.
+type PolicyDecision struct {
+	Failed   bool          `json:"failed"`
+	Outcome  string        `json:"outcome"`
+	ExitCode int           `json:"exit_code"`
+	Reason   string        `json:"reason,omitempty"`
+	Rules    []RuleOutcome `json:"rules,omitempty"`
+}
+
+// RuleOutcome reports how many results one policy rule matched.
+type RuleOutcome struct {
+	Rule      string   `json:"rule"`
+	Matches   int      `json:"matches"`
+	Threshold int      `json:"threshold"`
+	Triggered bool     `json:"triggered"`
+	URLs      []string `json:"urls,omitempty"`
+}
+
 // Result represents the result of a URL check
 type Result struct {
 	URL        string
@@ -18,7 +57,82 @@ type Result struct {
 	Status     string
 	Error      error
 	// Source indicates where the result came from: "robots" (disallow entry
-	// probed directly) or "bing" (discovered via Bing search). Empty defaults
-	// to "robots" for backward compatibility.
+	// probed directly), "bing" (discovered via Bing search) or "archive" (an
+	// entry only archived robots.txt versions list). Empty defaults to
+	// "robots" for backward compatibility.
 	Source string `json:"source,omitempty"`
+	// Archived is when archived robots.txt versions listed an "archive"
+	// result's entry.
+	Archived *ArchiveSpan `json:"archived,omitempty"`
+	// Fingerprints names the technologies recognised on the response (e.g.
+	// "jenkins", "wordpress"); empty unless fingerprinting was enabled.
+	Fingerprints []string `json:"fingerprints,omitempty"`
+	// Class labels what the response means for exposure: "open", "auth",
+	// "login", "forbidden", "waf", "not_found" or "other" (see
+	// pkg/classify). AuthScheme is set for "auth", e.g. "Basic".
+	Class      string `json:"class,omitempty"`
+	AuthScheme string `json:"auth_scheme,omitempty"`
+	// Findings are what analyzers noticed on the response (see
+	// pkg/scanner.Analyzer); empty unless analyzers were enabled.
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// ArchiveSpan is the first and last archive capture of robots.txt that
+// listed an entry. The entry may have been listed a little longer: archives
+// only see the file when they crawl it.
+type ArchiveSpan struct {
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// Finding is one analyzer's annotation on a result. Severity is "note",
+// "warning" or "error", as in the SARIF levels; Evidence is the excerpt that
+// triggered it, redacted where it would leak a secret.
+type Finding struct {
+	Analyzer string   `json:"analyzer"`
+	Message  string   `json:"message"`
+	Severity string   `json:"severity,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Evidence string   `json:"evidence,omitempty"`
+}
+
+// MarshalJSON writes Error as its message (null when nil) so results survive a
+// round trip through a JSON export.
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result
+	var msg *string
+	if r.Error != nil {
+		m := r.Error.Error()
+		msg = &m
+	}
+	return json.Marshal(struct {
+		plain
+		Error *string
+	}{plain(r), msg})
+}
+
+// UnmarshalJSON reads Error back as a plain error. Exports written before
+// errors were serialised hold {} for them, which loads as "unknown error".
+func (r *Result) UnmarshalJSON(b []byte) error {
+	type plain Result
+	aux := struct {
+		*plain
+		Error json.RawMessage
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	r.Error = nil
+	switch {
+	case len(aux.Error) == 0 || string(aux.Error) == "null":
+	case aux.Error[0] == '"':
+		var msg string
+		if err := json.Unmarshal(aux.Error, &msg); err != nil {
+			return err
+		}
+		r.Error = errors.New(msg)
+	default:
+		r.Error = errors.New("unknown error")
+	}
+	return nil
 }
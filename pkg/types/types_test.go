@@ -1,6 +1,8 @@
 package types_test
 
 import (
+	"encoding/json"
+	"errors"
 	"runtime"
 	"testing"
 
@@ -45,3 +47,33 @@ func TestResultStruct(t *testing.T) {
 		t.Errorf("Expected Error nil, got %v", result.Error)
 	}
 }
+
+func TestResultJSONRoundTrip(t *testing.T) {
+	in := []types.Result{
+		{URL: "http://x/a", StatusCode: 200, Status: "200 OK", Class: "open", Fingerprints: []string{"jenkins"}},
+		{URL: "http://x/b", Error: errors.New("connection refused")},
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []types.Result
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal %s: %v", b, err)
+	}
+	if out[0].URL != "http://x/a" || out[0].Class != "open" || out[0].Error != nil || len(out[0].Fingerprints) != 1 {
+		t.Errorf("first result = %+v", out[0])
+	}
+	if out[1].Error == nil || out[1].Error.Error() != "connection refused" {
+		t.Errorf("error not preserved: %+v", out[1])
+	}
+
+	// Exports from before errors were serialised hold {} for them.
+	var legacy types.Result
+	if err := json.Unmarshal([]byte(`{"URL":"http://x/c","StatusCode":0,"Status":"","Error":{}}`), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Error == nil {
+		t.Error("legacy {} error should load as non-nil")
+	}
+}